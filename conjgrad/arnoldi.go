@@ -0,0 +1,75 @@
+package conjgrad
+
+import (
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// Arnoldi runs the Arnoldi process on an operator t
+// for the given number of steps, starting from the
+// vector start.
+//
+// It returns an orthonormal basis q for the Krylov
+// subspace generated by start, along with the upper
+// Hessenberg matrix h for which t*Q_k = Q_{k+1}*h,
+// where Q_k holds the first k basis vectors.
+// Normally, q has steps+1 vectors and h is a
+// (steps+1) by steps matrix.
+//
+// If an invariant subspace is found early, the process
+// stops and h is square, with one row and column per
+// vector in q.
+// This is the non-symmetric analogue of Lanczos.
+func Arnoldi(t LinTran, start linalg.Vector, steps int) (q []linalg.Vector, h *DenseMatrix) {
+	if len(start) != t.Dim() {
+		panic("dimension mismatch")
+	}
+	norm := start.Mag()
+	if norm == 0 {
+		panic("starting vector must be non-zero")
+	}
+	q = []linalg.Vector{start.ScaledCopy(1 / norm)}
+
+	var columns []linalg.Vector
+	var scale float64
+	for j := 0; j < steps; j++ {
+		next, column := arnoldiStep(t, q)
+		columns = append(columns, column)
+		for _, x := range column[:j+1] {
+			scale = math.Max(scale, math.Abs(x))
+		}
+		nextMag := column[j+1]
+		if nextMag <= lanczosBreakdown*math.Max(scale, nextMag) || nextMag == 0 {
+			break
+		}
+		scale = math.Max(scale, nextMag)
+		q = append(q, next.Scale(1/nextMag))
+	}
+
+	h = NewDenseMatrix(len(q), len(columns))
+	for j, column := range columns {
+		for i := 0; i < len(column) && i < len(q); i++ {
+			h.Set(i, j, column[i])
+		}
+	}
+	return q, h
+}
+
+// arnoldiStep applies t to the last basis vector and
+// orthogonalizes the result against the basis using
+// modified Gram-Schmidt.
+//
+// It returns the unnormalized new vector and the new
+// Hessenberg column, whose last entry is the norm of
+// the new vector.
+func arnoldiStep(t LinTran, basis []linalg.Vector) (next, column linalg.Vector) {
+	next = t.Apply(basis[len(basis)-1])
+	column = make(linalg.Vector, len(basis)+1)
+	for i, vec := range basis {
+		column[i] = next.Dot(vec)
+		next.AddScaled(vec, -column[i])
+	}
+	column[len(basis)] = next.Mag()
+	return next, column
+}
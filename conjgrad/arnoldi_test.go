@@ -0,0 +1,46 @@
+package conjgrad
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestArnoldi(t *testing.T) {
+	lt, _, _ := nonSymmetricProblem()
+	q, h := Arnoldi(lt, linalg.Vector{1, 0, 2, -1}, 3)
+	if len(q) != 4 || h.Rows != 4 || h.Cols != 3 {
+		t.Fatalf("unexpected sizes: %d vectors, %dx%d matrix", len(q), h.Rows, h.Cols)
+	}
+	for i, v1 := range q {
+		for j, v2 := range q {
+			expected := 0.0
+			if i == j {
+				expected = 1
+			}
+			if math.Abs(v1.Dot(v2)-expected) > 1e-10 {
+				t.Errorf("basis vectors %d and %d are not orthonormal", i, j)
+			}
+		}
+	}
+	for j := 0; j < h.Cols; j++ {
+		expected := make(linalg.Vector, len(q[0]))
+		for i := 0; i < h.Rows; i++ {
+			if i > j+1 && h.At(i, j) != 0 {
+				t.Errorf("h is not upper Hessenberg at (%d, %d)", i, j)
+			}
+			expected.AddScaled(q[i], h.At(i, j))
+		}
+		checkSolution(t, lt.Apply(q[j]), expected)
+	}
+}
+
+func TestArnoldiBreakdown(t *testing.T) {
+	// The start vector lies in a 2D invariant subspace.
+	diag := Diagonal(linalg.Vector{1, 2, 3, 4})
+	q, h := Arnoldi(diag, linalg.Vector{1, 1, 0, 0}, 4)
+	if len(q) != 2 || h.Rows != 2 || h.Cols != 2 {
+		t.Fatalf("unexpected sizes: %d vectors, %dx%d matrix", len(q), h.Rows, h.Cols)
+	}
+}
@@ -0,0 +1,41 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// AutoPreconditioner picks a reasonable preconditioner
+// for t based on its concrete type:
+//
+//   - For a *SparseCSR, the zero-fill incomplete
+//     Cholesky factorization is used if it succeeds.
+//   - Otherwise, for a *SparseCSR, *DenseMatrix,
+//     *SymBand or Diagonal whose diagonal entries are
+//     all positive, Jacobi preconditioning is used.
+//   - In every other case, including any matrix-free
+//     LinTran, no preconditioning is done, and the
+//     result is the identity.
+//
+// No operator is ever applied, so inspecting t is
+// cheap, though computing the incomplete Cholesky
+// factor takes time proportional to its fill.
+func AutoPreconditioner(t LinTran) Preconditioner {
+	if sparse, ok := t.(*SparseCSR); ok {
+		if ic, err := NewIncompleteCholesky(sparse); err == nil {
+			return ic
+		}
+	}
+
+	var diagonal linalg.Vector
+	if d, ok := t.(Diagonal); ok {
+		diagonal = linalg.Vector(d)
+	} else if rt, ok := t.(rowTran); ok {
+		diagonal = rowTranDiagonalEntries(rt)
+	} else {
+		return identityPreconditioner{}
+	}
+	for _, x := range diagonal {
+		if !(x > 0) {
+			return identityPreconditioner{}
+		}
+	}
+	return &JacobiPreconditioner{diagonal: diagonal.Copy()}
+}
@@ -0,0 +1,26 @@
+package conjgrad
+
+import "testing"
+
+func TestAutoPreconditioner(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	dense := &DenseMatrix{Rows: 5, Cols: 5, Data: lt.M.Data}
+	if _, ok := AutoPreconditioner(dense).(*JacobiPreconditioner); !ok {
+		t.Error("expected Jacobi for a dense matrix")
+	}
+	if _, ok := AutoPreconditioner(lt).(identityPreconditioner); !ok {
+		t.Error("expected identity for a matrix-free operator")
+	}
+	sparse := NewSparseCSR(3, []int{0, 0, 1, 1, 1, 2, 2}, []int{0, 1, 0, 1, 2, 1, 2},
+		[]float64{2, -1, -1, 2, -1, -1, 2})
+	if _, ok := AutoPreconditioner(sparse).(*ICPreconditioner); !ok {
+		t.Error("expected incomplete Cholesky for a sparse SPD matrix")
+	}
+	negative := &DenseMatrix{Rows: 2, Cols: 2, Data: []float64{-1, 0, 0, 1}}
+	if _, ok := AutoPreconditioner(negative).(identityPreconditioner); !ok {
+		t.Error("expected identity for a non-positive diagonal")
+	}
+
+	m := AutoPreconditioner(dense)
+	checkSolution(t, SolvePreconditioned(dense, m, b, 1e-8, nil), realSolution)
+}
@@ -0,0 +1,76 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// SolveBiCGSTAB solves a system of linear equations
+// t*x = b for x using the stabilized biconjugate
+// gradient method.
+//
+// Unlike SolveStoppable, this does not require t to
+// be symmetric or positive-definite; it only requires
+// that t be invertible.
+//
+// The prec and cancelChan arguments behave as they do
+// for SolveStoppable.
+// If the method breaks down, the current approximate
+// solution is returned.
+func SolveBiCGSTAB(t LinTran, b linalg.Vector, prec float64,
+	cancelChan <-chan struct{}) linalg.Vector {
+	return SolveBiCGSTABDetailed(t, b, prec, cancelChan).Solution
+}
+
+// SolveBiCGSTABDetailed is like SolveBiCGSTAB, but it
+// returns a SolveResult.
+// Each iteration applies t twice.
+func SolveBiCGSTABDetailed(t LinTran, b linalg.Vector, prec float64,
+	cancelChan <-chan struct{}) SolveResult {
+	var iters int
+	solution := make(linalg.Vector, t.Dim())
+	residual := b.Copy()
+	shadow := b.Copy()
+
+	conjVec := make(linalg.Vector, t.Dim())
+	appliedConj := make(linalg.Vector, t.Dim())
+	rho, alpha, omega := 1.0, 1.0, 1.0
+
+	for ; residual.MaxAbs() > prec; iters++ {
+		newRho := shadow.Dot(residual)
+		if newRho == 0 {
+			break
+		}
+		beta := (newRho / rho) * (alpha / omega)
+		rho = newRho
+
+		conjVec.Add(appliedConj.Scale(-omega)).Scale(beta).Add(residual)
+		appliedConj = t.Apply(conjVec)
+
+		denom := shadow.Dot(appliedConj)
+		if denom == 0 {
+			break
+		}
+		alpha = rho / denom
+
+		s := residual.Copy().Add(appliedConj.Copy().Scale(-alpha))
+		solution.Add(conjVec.Copy().Scale(alpha))
+		if s.MaxAbs() <= prec {
+			iters++
+			break
+		}
+
+		appliedS := t.Apply(s)
+		sMag := appliedS.Dot(appliedS)
+		if sMag == 0 {
+			iters++
+			break
+		}
+		omega = appliedS.Dot(s) / sMag
+		solution.Add(s.Copy().Scale(omega))
+		residual = s.Add(appliedS.Scale(-omega))
+		if omega == 0 || cancelled(cancelChan) {
+			iters++
+			break
+		}
+	}
+
+	return detailedResult(t, b, solution, iters, prec)
+}
@@ -0,0 +1,30 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveBiCGSTAB(t *testing.T) {
+	lt, b, realSolution := nonSymmetricProblem()
+	solution := SolveBiCGSTAB(lt, b, 1e-10, nil)
+	checkSolution(t, solution, realSolution)
+}
+
+func nonSymmetricProblem() (lt MatLinTran, b, solution linalg.Vector) {
+	problem := &linalg.Matrix{
+		Rows: 4,
+		Cols: 4,
+		Data: []float64{
+			4, -1, 0.5, 0,
+			2, 5, -1, 1,
+			0, -3, 6, 2,
+			1, 0, 2, 3,
+		},
+	}
+	lt = MatLinTran{M: problem}
+	solution = linalg.Vector{1, -2, 3, 0.5}
+	b = lt.Apply(solution)
+	return
+}
@@ -0,0 +1,150 @@
+package conjgrad
+
+import (
+	"math"
+	"sync"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// blockDropTolerance is the relative A-norm below
+// which a search direction is considered linearly
+// dependent on the other directions in its block.
+const blockDropTolerance = 1e-12
+
+// SolveBlock solves t*x = b for every b in bs using
+// block conjugate gradients, where t is a symmetric
+// positive-definite linear operator.
+//
+// All of the right-hand sides share one set of
+// search directions, so fewer iterations are needed
+// than when solving each system separately.
+// The solve stops when every residual has a largest
+// component no greater than prec.
+//
+// Each block of search directions is made
+// t-orthonormal, and directions which turn out to be
+// linearly dependent on the rest of their block are
+// dropped, so the solve does not break down if the
+// block of residuals becomes rank-deficient.
+func SolveBlock(t LinTran, bs []linalg.Vector, prec float64) []linalg.Vector {
+	return SolveBlockParallel(t, bs, prec, 1)
+}
+
+// SolveBlockParallel is like SolveBlock, but it runs
+// up to workers calls to t.Apply at once.
+//
+// Since t.Apply is called from multiple goroutines,
+// it must be safe to call concurrently.
+// If workers is 1 or less, t.Apply is only called
+// from the current goroutine.
+func SolveBlockParallel(t LinTran, bs []linalg.Vector, prec float64,
+	workers int) []linalg.Vector {
+	return SolveBlockStoppable(t, bs, prec, workers, nil)
+}
+
+// SolveBlockStoppable is like SolveBlockParallel, but
+// it returns the current solutions once cancelChan is
+// closed.
+func SolveBlockStoppable(t LinTran, bs []linalg.Vector, prec float64,
+	workers int, cancelChan <-chan struct{}) []linalg.Vector {
+	solutions := make([]linalg.Vector, len(bs))
+	residuals := make([]linalg.Vector, len(bs))
+	for i, b := range bs {
+		solutions[i] = make(linalg.Vector, t.Dim())
+		residuals[i] = b.Copy()
+	}
+
+	var dirs, appliedDirs []linalg.Vector
+	for {
+		var candidates []linalg.Vector
+		for _, residual := range residuals {
+			if residual.MaxAbs() <= prec {
+				continue
+			}
+			candidate := residual.Copy()
+			for j, dir := range dirs {
+				candidate.Add(dir.Copy().Scale(-appliedDirs[j].Dot(candidate)))
+			}
+			candidates = append(candidates, candidate)
+		}
+		if len(candidates) == 0 {
+			break
+		}
+
+		dirs, appliedDirs = blockOrthonormalize(candidates, applyAll(t, candidates, workers))
+		if len(dirs) == 0 {
+			break
+		}
+
+		for i, residual := range residuals {
+			for j, dir := range dirs {
+				amount := dir.Dot(residual)
+				solutions[i].Add(dir.Copy().Scale(amount))
+				residual.Add(appliedDirs[j].Copy().Scale(-amount))
+			}
+		}
+
+		if cancelled(cancelChan) {
+			break
+		}
+	}
+
+	return solutions
+}
+
+// applyAll applies t to every vector in vecs, using
+// up to workers goroutines.
+func applyAll(t LinTran, vecs []linalg.Vector, workers int) []linalg.Vector {
+	res := make([]linalg.Vector, len(vecs))
+	if workers <= 1 || len(vecs) <= 1 {
+		for i, v := range vecs {
+			res[i] = t.Apply(v)
+		}
+		return res
+	}
+
+	indices := make(chan int, len(vecs))
+	for i := range vecs {
+		indices <- i
+	}
+	close(indices)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers && i < len(vecs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indices {
+				res[idx] = t.Apply(vecs[idx])
+			}
+		}()
+	}
+	wg.Wait()
+	return res
+}
+
+// blockOrthonormalize makes the vectors t-orthonormal
+// using modified Gram-Schmidt, given the result of
+// applying t to each of them.
+// Both slices are modified in place, and vectors that
+// are (nearly) linearly dependent are dropped.
+func blockOrthonormalize(vecs, applied []linalg.Vector) (dirs, appliedDirs []linalg.Vector) {
+	for i, vec := range vecs {
+		app := applied[i]
+		origNorm := math.Sqrt(math.Abs(vec.Dot(app)))
+		for j, dir := range dirs {
+			amount := -appliedDirs[j].Dot(vec)
+			vec.Add(dir.Copy().Scale(amount))
+			app.Add(appliedDirs[j].Copy().Scale(amount))
+		}
+		normSquared := vec.Dot(app)
+		if normSquared <= 0 || math.Sqrt(normSquared) <= blockDropTolerance*origNorm {
+			continue
+		}
+		scale := 1 / math.Sqrt(normSquared)
+		dirs = append(dirs, vec.Scale(scale))
+		appliedDirs = append(appliedDirs, app.Scale(scale))
+	}
+	return
+}
@@ -0,0 +1,37 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveBlock(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	bs := []linalg.Vector{
+		b,
+		b.Copy().Scale(2),
+		lt.Apply(linalg.Vector{1, 2, 3, 4, 5}),
+	}
+	expected := []linalg.Vector{
+		realSolution,
+		realSolution.Copy().Scale(2),
+		{1, 2, 3, 4, 5},
+	}
+	solutions := SolveBlock(lt, bs, 1e-8)
+	if len(solutions) != len(bs) {
+		t.Fatal("expected", len(bs), "solutions but got", len(solutions))
+	}
+	for i, solution := range solutions {
+		checkSolution(t, solution, expected[i])
+	}
+}
+
+func TestSolveBlockParallel(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	bs := []linalg.Vector{b, b.Copy().Scale(-3), lt.Apply(linalg.Vector{1, 2, 3, 4, 5})}
+	expected := []linalg.Vector{realSolution, realSolution.Copy().Scale(-3), {1, 2, 3, 4, 5}}
+	for i, solution := range SolveBlockParallel(lt, bs, 1e-8, 4) {
+		checkSolution(t, solution, expected[i])
+	}
+}
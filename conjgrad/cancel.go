@@ -0,0 +1,25 @@
+package conjgrad
+
+// cancelled reports whether cancelChan has been
+// closed (or has a value ready) without blocking.
+//
+// A nil cancelChan is never cancelled.
+// Every iterative solver calls this (directly or via
+// stopIteration) once per iteration and returns its
+// current approximation when it reports true.
+func cancelled(cancelChan <-chan struct{}) bool {
+	select {
+	case <-cancelChan:
+		return true
+	default:
+		return false
+	}
+}
+
+// stopIteration reports whether a solver loop which
+// has completed iters iterations should stop, either
+// because maxIter is positive and has been reached or
+// because cancelChan has been closed.
+func stopIteration(iters, maxIter int, cancelChan <-chan struct{}) bool {
+	return (maxIter > 0 && iters >= maxIter) || cancelled(cancelChan)
+}
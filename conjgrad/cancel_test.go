@@ -0,0 +1,142 @@
+package conjgrad
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestCancelledSolvers(t *testing.T) {
+	lt, b, _ := testProblem()
+	dense := &DenseMatrix{Rows: 5, Cols: 5, Data: lt.M.Data}
+	cancelChan := make(chan struct{})
+	close(cancelChan)
+
+	// A negative precision can never be met, so each
+	// solver only returns if it honors cancelChan.
+	solvers := map[string]func() linalg.Vector{
+		"CG": func() linalg.Vector {
+			return SolveStoppable(lt, nil, b, -1, cancelChan)
+		},
+		"BiCGSTAB": func() linalg.Vector {
+			return SolveBiCGSTAB(lt, b, -1, cancelChan)
+		},
+		"CR": func() linalg.Vector {
+			return SolveCR(lt, b, -1, cancelChan)
+		},
+		"MINRES": func() linalg.Vector {
+			return SolveMINRES(lt, b, -1, cancelChan)
+		},
+		"Block": func() linalg.Vector {
+			return SolveBlockStoppable(lt, []linalg.Vector{b}, -1, 1, cancelChan)[0]
+		},
+		"GMRES": func() linalg.Vector {
+			return SolveGMRESStoppable(lt, b, -1, 0, cancelChan)
+		},
+		"CGNR": func() linalg.Vector {
+			return SolveCGNRStoppable(lt, b, -1, cancelChan)
+		},
+		"Chebyshev": func() linalg.Vector {
+			return SolveChebyshevStoppable(lt, b, 1e-3, 10, -1, 0, cancelChan)
+		},
+		"FlexibleCG": func() linalg.Vector {
+			identity := func(r linalg.Vector) linalg.Vector { return r.Copy() }
+			return SolveFlexibleCGStoppable(lt, identity, b, -1, cancelChan)
+		},
+		"Pipelined": func() linalg.Vector {
+			return SolvePipelinedStoppable(lt, b, -1, cancelChan)
+		},
+		"SteepestDescent": func() linalg.Vector {
+			return SolveSteepestDescentStoppable(lt, b, -1, 0, cancelChan)
+		},
+		"LSQR": func() linalg.Vector {
+			return SolveLSQRStoppable(lt, b, -1, 0, cancelChan)
+		},
+		"LSQRDamped": func() linalg.Vector {
+			return SolveLSQRDampedStoppable(lt, b, 0.1, -1, 0, cancelChan)
+		},
+		"CGLS": func() linalg.Vector {
+			return SolveCGLSStoppable(lt, b, 0.1, -1, 0, cancelChan)
+		},
+		"GaussSeidel": func() linalg.Vector {
+			solution, err := SolveGaussSeidelStoppable(dense, b, -1, 0, cancelChan)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return solution
+		},
+		"SOR": func() linalg.Vector {
+			solution, err := SolveSORStoppable(dense, b, 1.2, -1, 0, cancelChan)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return solution
+		},
+		"Richardson": func() linalg.Vector {
+			return SolveRichardsonStoppable(lt, b, 1e-3, -1, 0, cancelChan)
+		},
+		"Deflated": func() linalg.Vector {
+			deflation := []linalg.Vector{{1, 0, 0, 0, 0}}
+			return SolveDeflatedStoppable(lt, nil, b, deflation, -1, cancelChan)
+		},
+		"NullspaceFiltered": func() linalg.Vector {
+			solution, _ := SolveNullspaceFilteredStoppable(lt, b, nil, -1, cancelChan)
+			return solution
+		},
+		"EisenstatSSOR": func() linalg.Vector {
+			return SolveEisenstatSSORStoppable(dense, 1, -1, b, cancelChan)
+		},
+		"TrustRegion": func() linalg.Vector {
+			return SolveTrustRegionStoppable(lt, b, 1e3, -1, cancelChan)
+		},
+		"Refined": func() linalg.Vector {
+			return SolveRefinedStoppable(lt, b, -1, cancelChan)
+		},
+		"LowMem": func() linalg.Vector {
+			return SolveLowMemStoppable(lt, b, -1, cancelChan)
+		},
+	}
+	for name, solver := range solvers {
+		solution := solver()
+		if len(solution) != len(b) {
+			t.Errorf("%s: bad solution length %d", name, len(solution))
+			continue
+		}
+		for _, x := range solution {
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				t.Errorf("%s: non-finite solution %v", name, solution)
+				break
+			}
+		}
+	}
+}
+
+func TestCancelledComplexSolvers(t *testing.T) {
+	lt := &denseTranC{dim: 2, data: []complex128{2, 1i, -1i, 3}}
+	b := linalg.CVector{1, 1i}
+	cancelChan := make(chan struct{})
+	close(cancelChan)
+	for name, solution := range map[string]linalg.CVector{
+		"Complex": SolveComplexStoppable(lt, b, -1, cancelChan),
+		"COCG":    SolveCOCGStoppable(lt, b, -1, cancelChan),
+	} {
+		if len(solution) != len(b) {
+			t.Errorf("%s: bad solution length %d", name, len(solution))
+		}
+	}
+}
+
+func TestStopIteration(t *testing.T) {
+	cancelChan := make(chan struct{})
+	if stopIteration(3, 0, cancelChan) || stopIteration(3, 4, nil) {
+		t.Error("stopped too early")
+	}
+	if !stopIteration(4, 4, nil) {
+		t.Error("did not stop at maxIter")
+	}
+	close(cancelChan)
+	if !stopIteration(0, 0, cancelChan) {
+		t.Error("did not stop after cancellation")
+	}
+}
@@ -0,0 +1,127 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// SolveCGNR finds the least-squares solution x to
+// t*x = b by running conjugate gradients on the
+// normal equations t'*t*x = t'*b.
+//
+// The product t'*t is never formed; instead, t and
+// its transpose are applied once each per iteration.
+//
+// The prec argument is a bound on the residual of the
+// normal equations. Once the largest element of
+// t'*(b-t*x) has an absolute value less than prec,
+// the current x is returned.
+func SolveCGNR(t LinTranRect, b linalg.Vector, prec float64) linalg.Vector {
+	return SolveCGNRStoppable(t, b, prec, nil)
+}
+
+// SolveCGNRStoppable is like SolveCGNR, but it returns
+// the current solution once cancelChan is closed.
+func SolveCGNRStoppable(t LinTranRect, b linalg.Vector, prec float64,
+	cancelChan <-chan struct{}) linalg.Vector {
+	if len(b) != t.Rows() {
+		panic("dimension mismatch")
+	}
+	solution := make(linalg.Vector, t.Cols())
+	residual := b.Copy()
+	normalResidual := t.ApplyTranspose(residual)
+	conjVec := normalResidual.Copy()
+	lastDot := normalResidual.Dot(normalResidual)
+
+	for normalResidual.MaxAbs() > prec {
+		applied := t.Apply(conjVec)
+		appliedDot := applied.Dot(applied)
+		if appliedDot == 0 {
+			break
+		}
+		optimalDistance := lastDot / appliedDot
+		solution.Add(conjVec.Copy().Scale(optimalDistance))
+		residual.Add(applied.Scale(-optimalDistance))
+
+		normalResidual = t.ApplyTranspose(residual)
+		residualDot := normalResidual.Dot(normalResidual)
+		conjVec.Scale(residualDot / lastDot).Add(normalResidual)
+		lastDot = residualDot
+
+		if cancelled(cancelChan) {
+			break
+		}
+	}
+
+	return solution
+}
+
+// SolveCGLS finds the x which minimizes
+// ||t*x-b||^2 + lambda^2*||x||^2 by running CG on the
+// damped normal equations (t'*t + lambda^2*I)*x = t'*b.
+//
+// As with SolveCGNR, t'*t is never formed; t and its
+// transpose are applied once each per iteration.
+// The damping makes the problem well-posed even when
+// t is rank-deficient.
+// This minimizes the same objective as
+// SolveLSQRDamped with damp = lambda, but LSQR is more
+// stable when t is ill-conditioned.
+//
+// With lambda = 0, this is plain CGLS, whose solution
+// may be dominated by noise along the smallest
+// singular values of a rank-deficient t.
+//
+// The solve stops when no component of the gradient
+// t'*(b-t*x) - lambda^2*x exceeds prec, or after
+// maxIter iterations if maxIter is positive.
+func SolveCGLS(t LinTranRect, b linalg.Vector, lambda, prec float64,
+	maxIter int) linalg.Vector {
+	return SolveCGLSStoppable(t, b, lambda, prec, maxIter, nil)
+}
+
+// SolveCGLSStoppable is like SolveCGLS, but it returns
+// the current solution once cancelChan is closed.
+func SolveCGLSStoppable(t LinTranRect, b linalg.Vector, lambda, prec float64,
+	maxIter int, cancelChan <-chan struct{}) linalg.Vector {
+	if len(b) != t.Rows() {
+		panic("dimension mismatch")
+	}
+	damp := lambda * lambda
+	solution := make(linalg.Vector, t.Cols())
+	residual := b.Copy()
+	gradient := t.ApplyTranspose(residual)
+	conjVec := gradient.Copy()
+	lastDot := gradient.Dot(gradient)
+
+	for iters := 0; gradient.MaxAbs() > prec; iters++ {
+		if stopIteration(iters, maxIter, cancelChan) {
+			break
+		}
+		applied := t.Apply(conjVec)
+		curvature := applied.Dot(applied) + damp*conjVec.Dot(conjVec)
+		if curvature == 0 {
+			break
+		}
+		step := lastDot / curvature
+		solution.AddScaled(conjVec, step)
+		residual.AddScaled(applied, -step)
+
+		gradient = t.ApplyTranspose(residual).AddScaled(solution, -damp)
+		gradientDot := gradient.Dot(gradient)
+		conjVec.Scale(gradientDot / lastDot).Add(gradient)
+		lastDot = gradientDot
+	}
+
+	return solution
+}
+
+// SolveNormalEquations is like SolveCGNR for a square
+// operator t, which need not be symmetric.
+//
+// If t is not a TransposableLinTran, this returns
+// ErrNotTransposable.
+func SolveNormalEquations(t LinTran, b linalg.Vector, prec float64) (linalg.Vector, error) {
+	transposable, ok := t.(TransposableLinTran)
+	if !ok {
+		return nil, ErrNotTransposable
+	}
+	return SolveCGNR(squareRect{transposable}, b, prec), nil
+}
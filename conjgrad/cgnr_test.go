@@ -0,0 +1,98 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveCGNR(t *testing.T) {
+	lt, b, realSolution := overdeterminedProblem()
+	solution := SolveCGNR(lt, b, 1e-10)
+	checkSolution(t, solution, realSolution)
+}
+
+func overdeterminedProblem() (lt MatLinTran, b, solution linalg.Vector) {
+	problem := &linalg.Matrix{
+		Rows: 5,
+		Cols: 3,
+		Data: []float64{
+			1, 0, 2,
+			-1, 3, 0,
+			2, 1, 1,
+			0, -2, 1,
+			1, 1, 1,
+		},
+	}
+	lt = MatLinTran{M: problem}
+
+	// The least-squares solution was computed exactly
+	// from the normal equations.
+	b = linalg.Vector{1, 1, 1, 1, 1}
+	solution = linalg.Vector{-1.0 / 6, 1.0 / 5, 5.0 / 6}
+	return
+}
+
+func TestSolveLSQR(t *testing.T) {
+	lt, b, realSolution := overdeterminedProblem()
+	solution := SolveLSQR(lt, b, 1e-12, 0)
+	checkSolution(t, solution, realSolution)
+
+	// For the underdetermined system x+y=2, the minimum
+	// norm solution is x=y=1.
+	under := MatLinTran{M: &linalg.Matrix{Rows: 1, Cols: 2, Data: []float64{1, 1}}}
+	checkSolution(t, SolveLSQR(under, linalg.Vector{2}, 1e-12, 10), linalg.Vector{1, 1})
+}
+
+func TestSolveLSQRDamped(t *testing.T) {
+	// Minimizing (x-1)^2 + (y-2)^2 + x^2 + y^2 gives
+	// x = 1/2 and y = 1.
+	ident := MatLinTran{M: linalg.NewMatrixIdentity(2)}
+	solution := SolveLSQRDamped(ident, linalg.Vector{1, 2}, 1, 1e-12, 0)
+	checkSolution(t, solution, linalg.Vector{0.5, 1})
+}
+
+func TestSolveCGLS(t *testing.T) {
+	lt, b, realSolution := overdeterminedProblem()
+	checkSolution(t, SolveCGLS(lt, b, 0, 1e-12, 0), realSolution)
+
+	// The columns are equal, so the problem is
+	// rank-deficient. With lambda = 1, the damped normal
+	// equations are [4 3; 3 4]*x = [6; 6].
+	rankDeficient := MatLinTran{M: &linalg.Matrix{
+		Rows: 3,
+		Cols: 2,
+		Data: []float64{1, 1, 1, 1, 1, 1},
+	}}
+	b = linalg.Vector{1, 2, 3}
+	expected := linalg.Vector{6.0 / 7, 6.0 / 7}
+	checkSolution(t, SolveCGLS(rankDeficient, b, 1, 1e-12, 0), expected)
+	checkSolution(t, SolveLSQRDamped(rankDeficient, b, 1, 1e-12, 0), expected)
+}
+
+func TestSolveNormalEquations(t *testing.T) {
+	lt, b, realSolution := nonSymmetricProblem()
+	dense := &DenseMatrix{Rows: lt.M.Rows, Cols: lt.M.Cols, Data: lt.M.Data}
+	solution, err := SolveNormalEquations(dense, b, 1e-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, solution, realSolution)
+
+	sparse := NewCOOBuilder(dense.Rows)
+	for i := 0; i < dense.Rows; i++ {
+		for j := 0; j < dense.Cols; j++ {
+			sparse.Add(i, j, dense.At(i, j))
+		}
+	}
+	solution, err = SolveNormalEquations(sparse.Build(), b, 1e-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, solution, realSolution)
+
+	funcTran := NewFuncTran(dense.Rows, dense.Apply)
+	if _, err := SolveNormalEquations(funcTran, b, 1e-10); err != ErrNotTransposable {
+		t.Errorf("expected ErrNotTransposable but got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// SolveChebyshev solves t*x = b for a symmetric
+// positive-definite t using Chebyshev iteration.
+//
+// The eigenvalues of t must lie in the interval
+// [lambdaMin, lambdaMax], which can be estimated with
+// EstimateEigenvalueBounds.
+// Unlike CG, no inner products are computed, so the
+// only global operation per iteration is the residual
+// check.
+//
+// The solve stops when no component of the residual
+// exceeds prec, or after maxIter iterations if maxIter
+// is positive.
+//
+// This panics unless 0 < lambdaMin <= lambdaMax.
+func SolveChebyshev(t LinTran, b linalg.Vector, lambdaMin, lambdaMax, prec float64,
+	maxIter int) linalg.Vector {
+	return SolveChebyshevStoppable(t, b, lambdaMin, lambdaMax, prec, maxIter, nil)
+}
+
+// SolveChebyshevStoppable is like SolveChebyshev, but
+// it returns the current solution once cancelChan is
+// closed.
+func SolveChebyshevStoppable(t LinTran, b linalg.Vector, lambdaMin, lambdaMax,
+	prec float64, maxIter int, cancelChan <-chan struct{}) linalg.Vector {
+	if !(lambdaMin > 0 && lambdaMin <= lambdaMax) {
+		panic("invalid eigenvalue bounds")
+	}
+	center := (lambdaMax + lambdaMin) / 2
+	radius := (lambdaMax - lambdaMin) / 2
+
+	solution := make(linalg.Vector, t.Dim())
+	residual := b.Copy()
+	step := residual.ScaledCopy(1 / center)
+
+	var rho, sigma float64
+	if radius != 0 {
+		sigma = center / radius
+		rho = 1 / sigma
+	}
+
+	for iters := 0; residual.NormInf() > prec; iters++ {
+		if maxIter > 0 && iters >= maxIter {
+			break
+		}
+		solution.Add(step)
+		residual.AddScaled(t.Apply(step), -1)
+
+		if radius == 0 {
+			// With a single eigenvalue, this is just Richardson
+			// iteration with the optimal step size.
+			copy(step, residual)
+			step.Scale(1 / center)
+		} else {
+			nextRho := 1 / (2*sigma - rho)
+			step.Scale(nextRho*rho).AddScaled(residual, 2*nextRho/radius)
+			rho = nextRho
+		}
+
+		if cancelled(cancelChan) {
+			break
+		}
+	}
+
+	return solution
+}
+
+type polyPreconditioner struct {
+	t         LinTran
+	lambdaMin float64
+	lambdaMax float64
+	degree    int
+}
+
+// NewPolyPreconditioner creates a Preconditioner which
+// approximates the inverse of t by a polynomial in t
+// of the given degree.
+//
+// The polynomial comes from running degree+1 steps of
+// Chebyshev iteration, so the eigenvalues of t must
+// lie in [lambdaMin, lambdaMax].
+// Each ApplyInverse costs degree+1 calls to t.Apply,
+// so a higher degree reduces the number of outer CG
+// iterations at the price of more work per iteration.
+//
+// This panics unless 0 < lambdaMin <= lambdaMax and
+// degree is non-negative.
+func NewPolyPreconditioner(t LinTran, lambdaMin, lambdaMax float64,
+	degree int) Preconditioner {
+	if !(lambdaMin > 0 && lambdaMin <= lambdaMax) {
+		panic("invalid eigenvalue bounds")
+	}
+	if degree < 0 {
+		panic("negative degree")
+	}
+	return &polyPreconditioner{
+		t:         t,
+		lambdaMin: lambdaMin,
+		lambdaMax: lambdaMax,
+		degree:    degree,
+	}
+}
+
+func (p *polyPreconditioner) ApplyInverse(r linalg.Vector) linalg.Vector {
+	// A negative precision makes every solve run exactly
+	// degree+1 steps, so the same polynomial is always
+	// applied and the preconditioner stays symmetric.
+	return SolveChebyshev(p.t, r, p.lambdaMin, p.lambdaMax, -1, p.degree+1)
+}
@@ -0,0 +1,33 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveChebyshev(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	alphas, betas := Lanczos(lt, linalg.Vector{1, 1, 1, 1, 1}, 5)
+	eigs := tridiagEigenvalues(alphas, betas)
+	lambdaMin, lambdaMax := eigs[0]*0.99, eigs[len(eigs)-1]*1.01
+
+	solution := SolveChebyshev(lt, b, lambdaMin, lambdaMax, 1e-10, 0)
+	checkSolution(t, solution, realSolution)
+}
+
+func TestSolveChebyshevIdentity(t *testing.T) {
+	b := linalg.Vector{1, 2, 3}
+	solution := SolveChebyshev(ScaledTran(2, IdentityTran(3)), b, 2, 2, 1e-10, 0)
+	checkSolution(t, solution, linalg.Vector{0.5, 1, 1.5})
+}
+
+func TestPolyPreconditioner(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	alphas, betas := Lanczos(lt, linalg.Vector{1, 1, 1, 1, 1}, 5)
+	eigs := tridiagEigenvalues(alphas, betas)
+	m := NewPolyPreconditioner(lt, eigs[0]*0.99, eigs[len(eigs)-1]*1.01, 3)
+
+	solution := SolvePreconditioned(lt, m, b, 1e-10, nil)
+	checkSolution(t, solution, realSolution)
+}
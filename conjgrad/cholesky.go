@@ -0,0 +1,69 @@
+package conjgrad
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// CholeskyFactor is the Cholesky factorization of a
+// symmetric positive-definite matrix A.
+type CholeskyFactor struct {
+	// L is the lower-triangular matrix for which
+	// A = L*L'.
+	L *DenseMatrix
+}
+
+// Cholesky computes the Cholesky factorization of a
+// dense symmetric positive-definite matrix in O(n^3)
+// time.
+//
+// Only the lower triangle of a is accessed.
+//
+// An error is returned if a is not square or if a
+// non-positive pivot is encountered, meaning that a
+// is not positive-definite.
+func Cholesky(a *DenseMatrix) (*CholeskyFactor, error) {
+	if a.Rows != a.Cols {
+		return nil, errors.New("matrix is not square")
+	}
+	n := a.Rows
+	lower := NewDenseMatrix(n, n)
+	for i := 0; i < n; i++ {
+		rowI := linalg.Vector(lower.Data[i*n : i*n+i])
+		for j := 0; j < i; j++ {
+			rowJ := linalg.Vector(lower.Data[j*n : j*n+j])
+			sum := a.At(i, j) - rowI[:j].DotFast(rowJ)
+			lower.Set(i, j, sum/lower.At(j, j))
+		}
+		pivot := a.At(i, i) - rowI.DotFast(rowI)
+		if pivot <= 0 {
+			return nil, fmt.Errorf("non-positive pivot %g at row %d", pivot, i)
+		}
+		lower.Set(i, i, math.Sqrt(pivot))
+	}
+	return &CholeskyFactor{L: lower}, nil
+}
+
+// Solve solves A*x = b using forward and back
+// substitution.
+func (c *CholeskyFactor) Solve(b linalg.Vector) linalg.Vector {
+	n := c.L.Rows
+	if len(b) != n {
+		panic("dimension mismatch")
+	}
+	res := b.Copy()
+	for i := 0; i < n; i++ {
+		row := linalg.Vector(c.L.Data[i*n : i*n+i])
+		res[i] = (res[i] - row.DotFast(res[:i])) / c.L.At(i, i)
+	}
+	for i := n - 1; i >= 0; i-- {
+		res[i] /= c.L.At(i, i)
+		for j := 0; j < i; j++ {
+			res[j] -= c.L.At(i, j) * res[i]
+		}
+	}
+	return res
+}
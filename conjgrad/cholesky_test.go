@@ -0,0 +1,27 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestCholesky(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	dense := &DenseMatrix{Rows: lt.M.Rows, Cols: lt.M.Cols, Data: lt.M.Data}
+	factor, err := Cholesky(dense)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, factor.Solve(b), realSolution)
+
+	product := factor.L.Matrix().Mul(factor.L.Matrix().Transpose())
+	checkSolution(t, linalg.Vector(product.Data), linalg.Vector(dense.Data))
+}
+
+func TestCholeskyIndefinite(t *testing.T) {
+	dense := &DenseMatrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 2, 1}}
+	if _, err := Cholesky(dense); err == nil {
+		t.Error("expected error for indefinite matrix")
+	}
+}
@@ -0,0 +1,55 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// A SolverFunc solves t*x = b until no component of
+// the residual exceeds prec, and reports how the
+// solve went.
+//
+// SolveDetailed is a SolverFunc, and the other
+// Detailed solvers can be adapted with a closure, e.g.
+//
+//	func(t LinTran, b linalg.Vector, prec float64) SolveResult {
+//		return SolveGMRESDetailed(t, b, prec, 0, nil)
+//	}
+//
+// It is not called Solver because that name belongs
+// to the reusable CG workspace.
+type SolverFunc func(t LinTran, b linalg.Vector, prec float64) SolveResult
+
+// A Comparison summarizes one solver's run in
+// Compare.
+// It is a SolveResult plus the operator applications
+// that produced it, which a bare SolveResult cannot
+// report.
+type Comparison struct {
+	SolveResult
+
+	// Applies is the total number of calls to Apply,
+	// ApplyInto and ApplyTranspose made by the solver,
+	// including any it made to compute FinalResidual.
+	Applies int
+}
+
+// Compare runs every solver on the system t*x = b
+// and reports how each one did.
+//
+// Each solver gets its own CountingTran around t, so
+// the number of operator applications can be
+// compared fairly between methods which do different
+// amounts of work per iteration.
+// The FinalResidual and Converged fields are the
+// ones reported by each solver, so Compare does no
+// work of its own that Applies would miss.
+// The solvers run one at a time, in no particular
+// order.
+func Compare(t LinTran, b linalg.Vector, prec float64,
+	solvers map[string]SolverFunc) map[string]Comparison {
+	res := make(map[string]Comparison, len(solvers))
+	for name, solver := range solvers {
+		counter := NewCountingTran(t)
+		result := solver(counter, b, prec)
+		res[name] = Comparison{SolveResult: result, Applies: counter.ApplyCount()}
+	}
+	return res
+}
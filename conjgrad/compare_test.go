@@ -0,0 +1,41 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestCompare(t *testing.T) {
+	band, b := lowMemProblem(100)
+	results := Compare(band, b, 1e-8, map[string]SolverFunc{
+		"CG": SolveDetailed,
+		"BiCGSTAB": func(t LinTran, b linalg.Vector, prec float64) SolveResult {
+			return SolveBiCGSTABDetailed(t, b, prec, nil)
+		},
+		"GMRES": func(t LinTran, b linalg.Vector, prec float64) SolveResult {
+			return SolveGMRESDetailed(t, b, prec, 0, nil)
+		},
+		"MINRES": func(t LinTran, b linalg.Vector, prec float64) SolveResult {
+			return SolveMINRESDetailed(t, b, prec, nil)
+		},
+	})
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results but got %d", len(results))
+	}
+	for name, res := range results {
+		if !res.Converged {
+			t.Errorf("%s: did not converge (residual %g)", name, res.FinalResidual)
+		}
+		if res.Iterations == 0 || res.Applies < res.Iterations {
+			t.Errorf("%s: %d iterations with %d applies", name, res.Iterations, res.Applies)
+		}
+	}
+	// The final residual costs CG one more apply.
+	if cg := results["CG"]; cg.Applies <= cg.Iterations {
+		t.Errorf("CG made %d applies in %d iterations", cg.Applies, cg.Iterations)
+	}
+	if bicg := results["BiCGSTAB"]; bicg.Applies < 2*bicg.Iterations-1 {
+		t.Errorf("BiCGSTAB made %d applies in %d iterations", bicg.Applies, bicg.Iterations)
+	}
+}
@@ -0,0 +1,116 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// A LinTranC is a square linear transformation on
+// complex vectors.
+type LinTranC interface {
+	// Dim returns the number of dimensions in
+	// the input and output vectors of this
+	// linear transformation.
+	Dim() int
+
+	// Apply applies this linear transformation
+	// to a vector and returns the result.
+	Apply(v linalg.CVector) linalg.CVector
+}
+
+// SolveComplex solves a system of linear equations
+// t*x = b for x, where t is a Hermitian
+// positive-definite linear operator.
+//
+// This is the same as conjugate gradients for real
+// systems, except that inner products conjugate their
+// first argument.
+// For Hermitian positive-definite t, the inner products
+// used to compute step sizes are real, and their
+// imaginary parts (which only come from rounding
+// error) are discarded.
+//
+// The prec argument specifies a bound on the largest
+// absolute value of any component of the residual.
+func SolveComplex(t LinTranC, b linalg.CVector, prec float64) linalg.CVector {
+	return SolveComplexStoppable(t, b, prec, nil)
+}
+
+// SolveComplexStoppable is like SolveComplex, but it
+// returns the current solution once cancelChan is
+// closed.
+func SolveComplexStoppable(t LinTranC, b linalg.CVector, prec float64,
+	cancelChan <-chan struct{}) linalg.CVector {
+	residual := b.Copy()
+	solution := make(linalg.CVector, t.Dim())
+	conjVec := residual.Copy()
+	lastResidualDot := real(residual.Dot(residual))
+
+	for residual.MaxAbs() > prec && !cancelled(cancelChan) {
+		appliedConj := t.Apply(conjVec)
+		curvature := real(conjVec.Dot(appliedConj))
+		if curvature == 0 {
+			break
+		}
+		optimalDistance := complex(lastResidualDot/curvature, 0)
+		solution.AddScaled(conjVec, optimalDistance)
+		residual.AddScaled(appliedConj, -optimalDistance)
+
+		residualDot := real(residual.Dot(residual))
+		conjVec.Scale(complex(residualDot/lastResidualDot, 0)).Add(residual)
+		lastResidualDot = residualDot
+	}
+
+	return solution
+}
+
+// SolveCOCG solves a system of linear equations
+// t*x = b for x, where t is complex symmetric, i.e.
+// equal to its own (unconjugated) transpose.
+//
+// This is the conjugate orthogonal CG method.
+// It differs from SolveComplex only in its inner
+// products, which use linalg.CVector.DotUnconj
+// instead of the Hermitian inner product.
+// SolveComplex targets Hermitian positive-definite
+// operators, whereas SolveCOCG targets the complex
+// symmetric, non-Hermitian operators which arise in
+// frequency-domain problems.
+//
+// Since the bilinear form is not positive-definite,
+// the method can break down when one of its
+// denominators is zero.
+// In that case, the current solution is returned.
+//
+// The prec argument specifies a bound on the largest
+// absolute value of any component of the residual.
+func SolveCOCG(t LinTranC, b linalg.CVector, prec float64) linalg.CVector {
+	return SolveCOCGStoppable(t, b, prec, nil)
+}
+
+// SolveCOCGStoppable is like SolveCOCG, but it returns
+// the current solution once cancelChan is closed.
+func SolveCOCGStoppable(t LinTranC, b linalg.CVector, prec float64,
+	cancelChan <-chan struct{}) linalg.CVector {
+	residual := b.Copy()
+	solution := make(linalg.CVector, t.Dim())
+	conjVec := residual.Copy()
+	lastResidualDot := residual.DotUnconj(residual)
+
+	for residual.MaxAbs() > prec && !cancelled(cancelChan) {
+		if lastResidualDot == 0 {
+			break
+		}
+		appliedConj := t.Apply(conjVec)
+		curvature := conjVec.DotUnconj(appliedConj)
+		if curvature == 0 {
+			break
+		}
+		optimalDistance := lastResidualDot / curvature
+		solution.AddScaled(conjVec, optimalDistance)
+		residual.AddScaled(appliedConj, -optimalDistance)
+
+		residualDot := residual.DotUnconj(residual)
+		conjVec.Scale(residualDot / lastResidualDot).Add(residual)
+		lastResidualDot = residualDot
+	}
+
+	return solution
+}
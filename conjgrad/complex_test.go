@@ -0,0 +1,74 @@
+package conjgrad
+
+import (
+	"math/cmplx"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+type denseTranC struct {
+	dim  int
+	data []complex128
+}
+
+func (d *denseTranC) Dim() int {
+	return d.dim
+}
+
+func (d *denseTranC) Apply(v linalg.CVector) linalg.CVector {
+	res := make(linalg.CVector, d.dim)
+	for i := range res {
+		for j, x := range v {
+			res[i] += d.data[i*d.dim+j] * x
+		}
+	}
+	return res
+}
+
+func checkSolutionC(t *testing.T, actual, expected linalg.CVector) {
+	for i, x := range expected {
+		if cmplx.Abs(x-actual[i]) > 1e-8 || cmplx.IsNaN(actual[i]) {
+			t.Error("expected solution", expected, "but got", actual)
+			break
+		}
+	}
+}
+
+func TestSolveComplex(t *testing.T) {
+	op := &denseTranC{
+		dim: 3,
+		data: []complex128{
+			4, 1i, 1 - 1i,
+			-1i, 3, 0.5,
+			1 + 1i, 0.5, 5,
+		},
+	}
+	expected := linalg.CVector{1 + 2i, -1, 0.5i}
+	solution := SolveComplex(op, op.Apply(expected), 1e-12)
+	checkSolutionC(t, solution, expected)
+}
+
+func TestSolveCOCG(t *testing.T) {
+	// A complex symmetric operator which is not
+	// Hermitian.
+	op := &denseTranC{
+		dim: 3,
+		data: []complex128{
+			4 + 1i, 1i, 1 - 1i,
+			1i, 3 - 2i, 0.5,
+			1 - 1i, 0.5, 5 + 0.5i,
+		},
+	}
+	expected := linalg.CVector{1 + 2i, -1, 0.5i}
+	solution := SolveCOCG(op, op.Apply(expected), 1e-12)
+	checkSolutionC(t, solution, expected)
+
+	// The residual b = (1, i) has 1*1 + i*i = 0, so the
+	// method breaks down immediately.
+	identity := &denseTranC{dim: 2, data: []complex128{1, 0, 0, 1}}
+	solution = SolveCOCG(identity, linalg.CVector{1, 1i}, 1e-12)
+	if len(solution) != 2 || solution[0] != 0 || solution[1] != 0 {
+		t.Error("unexpected solution after breakdown", solution)
+	}
+}
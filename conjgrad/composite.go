@@ -0,0 +1,280 @@
+package conjgrad
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// SumTran creates a LinTran which represents the sum
+// of the given linear transformations.
+//
+// All of the transformations must have the same
+// dimension, and at least one must be given.
+func SumTran(ts ...LinTran) LinTran {
+	if len(ts) == 0 {
+		panic("no operators to sum")
+	}
+	for i, t := range ts[1:] {
+		if t.Dim() != ts[0].Dim() {
+			panic(fmt.Sprintf("dimension mismatch: operator %d has dimension %d, not %d",
+				i+1, t.Dim(), ts[0].Dim()))
+		}
+	}
+	return sumTran(ts)
+}
+
+type sumTran []LinTran
+
+func (s sumTran) Dim() int {
+	return s[0].Dim()
+}
+
+func (s sumTran) Apply(v linalg.Vector) linalg.Vector {
+	res := s[0].Apply(v).Copy()
+	for _, t := range s[1:] {
+		res.Add(t.Apply(v))
+	}
+	return res
+}
+
+// ProductTran creates a LinTran which represents the
+// product a*b, which applies b and then applies a.
+//
+// The two transformations must have the same
+// dimension.
+func ProductTran(a, b LinTran) LinTran {
+	if a.Dim() != b.Dim() {
+		panic(fmt.Sprintf("dimension mismatch: cannot multiply %d-dimensional operator "+
+			"by %d-dimensional operator", a.Dim(), b.Dim()))
+	}
+	return productTran{a, b}
+}
+
+type productTran struct {
+	a LinTran
+	b LinTran
+}
+
+func (p productTran) Dim() int {
+	return p.a.Dim()
+}
+
+func (p productTran) Apply(v linalg.Vector) linalg.Vector {
+	return p.a.Apply(p.b.Apply(v))
+}
+
+// IdentityTran creates an identity LinTran of the
+// given dimension.
+//
+// The identity's Apply method returns a copy of its
+// argument, so its result can be modified without
+// affecting the input.
+func IdentityTran(dim int) LinTran {
+	return identityTran(dim)
+}
+
+type identityTran int
+
+func (i identityTran) Dim() int {
+	return int(i)
+}
+
+func (i identityTran) Apply(v linalg.Vector) linalg.Vector {
+	if len(v) != int(i) {
+		panic("dimension mismatch")
+	}
+	return v.Copy()
+}
+
+// ScaledTran creates a LinTran which represents s*t.
+func ScaledTran(s float64, t LinTran) LinTran {
+	return scaledTran{s, t}
+}
+
+type scaledTran struct {
+	s float64
+	t LinTran
+}
+
+func (s scaledTran) Dim() int {
+	return s.t.Dim()
+}
+
+func (s scaledTran) Apply(v linalg.Vector) linalg.Vector {
+	return s.t.Apply(v).Copy().Scale(s.s)
+}
+
+// KroneckerTran creates a LinTran which represents the
+// Kronecker product of a and b, without ever forming
+// the product.
+//
+// The input is treated as vec(X), the columns of a
+// b.Dim() by a.Dim() matrix X stacked on top of each
+// other, and the result is vec(B*X*A').
+// Only the Apply methods of a and b are used.
+func KroneckerTran(a, b LinTran) LinTran {
+	return kroneckerTran{a, b}
+}
+
+type kroneckerTran struct {
+	a LinTran
+	b LinTran
+}
+
+func (k kroneckerTran) Dim() int {
+	return k.a.Dim() * k.b.Dim()
+}
+
+func (k kroneckerTran) Apply(v linalg.Vector) linalg.Vector {
+	if len(v) != k.Dim() {
+		panic("dimension mismatch")
+	}
+	rows, cols := k.b.Dim(), k.a.Dim()
+
+	// Compute Y = B*X one column at a time.
+	product := make(linalg.Vector, len(v))
+	for j := 0; j < cols; j++ {
+		copy(product[j*rows:(j+1)*rows], k.b.Apply(v[j*rows:(j+1)*rows]))
+	}
+
+	// Row i of Y*A' is A times row i of Y.
+	res := make(linalg.Vector, len(v))
+	row := make(linalg.Vector, cols)
+	for i := 0; i < rows; i++ {
+		for j := range row {
+			row[j] = product[j*rows+i]
+		}
+		for j, x := range k.a.Apply(row) {
+			res[j*rows+i] = x
+		}
+	}
+	return res
+}
+
+// BlockDiagonalTran creates a LinTran which represents
+// a block-diagonal operator with the given blocks.
+//
+// The input vector is split into consecutive segments,
+// one per block, and the blocks are applied to their
+// segments concurrently.
+// If the same operator appears as more than one block,
+// its Apply method must be safe to call concurrently.
+func BlockDiagonalTran(blocks ...LinTran) LinTran {
+	if len(blocks) == 0 {
+		panic("no blocks given")
+	}
+	offsets := make([]int, len(blocks)+1)
+	for i, block := range blocks {
+		if block.Dim() <= 0 {
+			panic(fmt.Sprintf("block %d has non-positive dimension %d", i, block.Dim()))
+		}
+		offsets[i+1] = offsets[i] + block.Dim()
+	}
+	return &blockDiagonalTran{blocks: blocks, offsets: offsets}
+}
+
+type blockDiagonalTran struct {
+	blocks  []LinTran
+	offsets []int
+}
+
+func (b *blockDiagonalTran) Dim() int {
+	return b.offsets[len(b.blocks)]
+}
+
+func (b *blockDiagonalTran) Apply(v linalg.Vector) linalg.Vector {
+	if len(v) != b.Dim() {
+		panic("dimension mismatch")
+	}
+	res := make(linalg.Vector, len(v))
+	var wg sync.WaitGroup
+	for i, block := range b.blocks {
+		wg.Add(1)
+		go func(block LinTran, start, end int) {
+			defer wg.Done()
+			copy(res[start:end], block.Apply(v[start:end]))
+		}(block, b.offsets[i], b.offsets[i+1])
+	}
+	wg.Wait()
+	return res
+}
+
+// Outer computes the outer product u*v' as a
+// len(u) by len(v) matrix.
+func Outer(u, v linalg.Vector) *DenseMatrix {
+	res := NewDenseMatrix(len(u), len(v))
+	for i, x := range u {
+		row := linalg.Vector(res.Data[i*len(v) : (i+1)*len(v)])
+		row.AddScaled(v, x)
+	}
+	return res
+}
+
+// OuterTran creates a LinTran which represents the
+// rank-one matrix u*v' without storing it, so that
+// each Apply takes O(n) time.
+//
+// The vectors must have the same length.
+func OuterTran(u, v linalg.Vector) LinTran {
+	if len(u) != len(v) {
+		panic(fmt.Sprintf("dimension mismatch: outer product of %d and %d vectors "+
+			"is not square", len(u), len(v)))
+	}
+	return outerTran{u: u.Copy(), v: v.Copy()}
+}
+
+type outerTran struct {
+	u linalg.Vector
+	v linalg.Vector
+}
+
+func (o outerTran) Dim() int {
+	return len(o.u)
+}
+
+func (o outerTran) Apply(x linalg.Vector) linalg.Vector {
+	return o.u.ScaledCopy(o.v.Dot(x))
+}
+
+// SaddlePointTran creates a LinTran which represents
+// the symmetric saddle-point (KKT) matrix
+//
+//	[A  B']
+//	[B  0 ]
+//
+// for an n by n symmetric a and an m by n b, using
+// only b's Apply and ApplyTranspose.
+// Its dimension is n+m.
+//
+// The result is indefinite, so it should be solved
+// with a method like SolveMINRES rather than CG.
+func SaddlePointTran(a LinTran, b LinTranRect) LinTran {
+	if b.Cols() != a.Dim() {
+		panic(fmt.Sprintf("dimension mismatch: constraint matrix has %d columns, not %d",
+			b.Cols(), a.Dim()))
+	}
+	return saddlePointTran{a: a, b: b}
+}
+
+type saddlePointTran struct {
+	a LinTran
+	b LinTranRect
+}
+
+func (s saddlePointTran) Dim() int {
+	return s.a.Dim() + s.b.Rows()
+}
+
+func (s saddlePointTran) Apply(v linalg.Vector) linalg.Vector {
+	if len(v) != s.Dim() {
+		panic("dimension mismatch")
+	}
+	n := s.a.Dim()
+	x, y := v[:n], v[n:]
+	res := make(linalg.Vector, 0, len(v))
+	res = append(res, s.a.Apply(x)...)
+	res.Add(s.b.ApplyTranspose(y))
+	return append(res, s.b.Apply(x)...)
+}
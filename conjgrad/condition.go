@@ -0,0 +1,59 @@
+package conjgrad
+
+import (
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// cgCoefficients records the step sizes (alphas) and
+// direction update coefficients (betas) used by CG.
+type cgCoefficients struct {
+	alphas []float64
+	betas  []float64
+}
+
+func (c *cgCoefficients) add(alpha, beta float64, first bool) {
+	if !first {
+		c.betas = append(c.betas, beta)
+	}
+	c.alphas = append(c.alphas, alpha)
+}
+
+// lanczosMatrix computes the tridiagonal matrix that
+// the Lanczos process would have produced for the
+// same Krylov subspace.
+func (c *cgCoefficients) lanczosMatrix() (diag, offDiag linalg.Vector) {
+	diag = make(linalg.Vector, len(c.alphas))
+	offDiag = make(linalg.Vector, len(c.betas))
+	for i, alpha := range c.alphas {
+		diag[i] = 1 / alpha
+		if i > 0 {
+			diag[i] += c.betas[i-1] / c.alphas[i-1]
+			offDiag[i-1] = math.Sqrt(c.betas[i-1]) / c.alphas[i-1]
+		}
+	}
+	return
+}
+
+// SolveWithConditionEstimate is like SolvePrec without
+// a preconditioner, but it also estimates the condition
+// number of t.
+//
+// The CG coefficients determine the tridiagonal matrix
+// of the underlying Lanczos process, and the estimate
+// is the ratio between its largest and smallest
+// eigenvalues.
+// This underestimates the true condition number, but
+// it gets closer as more iterations are performed.
+// If no iterations are performed, the estimate is 0.
+func SolveWithConditionEstimate(t LinTran, b linalg.Vector,
+	prec float64) (linalg.Vector, float64) {
+	var coeffs cgCoefficients
+	solution := solve(t, b, &SolveOptions{Tolerance: prec, coefficients: &coeffs}).Solution
+	if len(coeffs.alphas) == 0 {
+		return solution, 0
+	}
+	ritz := tridiagEigenvalues(coeffs.lanczosMatrix())
+	return solution, ritz[len(ritz)-1] / ritz[0]
+}
@@ -0,0 +1,25 @@
+package conjgrad
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveWithConditionEstimate(t *testing.T) {
+	diag := Diagonal{1, 2, 5, 10, 20}
+	b := linalg.Vector{1, 1, 1, 1, 1}
+	solution, cond := SolveWithConditionEstimate(diag, b, 1e-12)
+	checkSolution(t, solution, linalg.Vector{1, 0.5, 0.2, 0.1, 0.05})
+	if math.Abs(cond-20) > 1e-6 {
+		t.Error("expected condition number 20 but got", cond)
+	}
+
+	lt, b1, realSolution := testProblem()
+	solution, cond = SolveWithConditionEstimate(lt, b1, 1e-8)
+	checkSolution(t, solution, realSolution)
+	if cond < 1000 {
+		t.Error("condition number estimate is too small:", cond)
+	}
+}
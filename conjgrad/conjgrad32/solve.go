@@ -0,0 +1,84 @@
+// Package conjgrad32 is a single-precision version
+// of the conjgrad package, for problems where memory
+// matters more than accuracy.
+//
+// Every vector and operator is float32 end-to-end.
+package conjgrad32
+
+const residualUpdateFrequency = 20
+
+// A LinTran32 is a square, single-precision linear
+// transformation.
+type LinTran32 interface {
+	// Dim returns the number of dimensions in
+	// the input and output vectors of this
+	// linear transformation.
+	Dim() int
+
+	// Apply applies this linear transformation
+	// to a vector and returns the result.
+	Apply(v Vector32) Vector32
+}
+
+// SolveStoppable32 is a single-precision version of
+// conjgrad.SolveStoppable.
+//
+// If precond is nil, then no preconditioning is used.
+func SolveStoppable32(t, precond LinTran32, b Vector32, prec float32,
+	cancelChan <-chan struct{}) Vector32 {
+	var conjVec Vector32
+	var lastResidualDot float32
+
+	residual := b.Copy()
+	solution := make(Vector32, t.Dim())
+
+	for i := 0; residual.MaxAbs() > prec; i++ {
+		z := residual
+		if precond != nil {
+			z = precond.Apply(residual)
+		}
+		residualDot := z.Dot(residual)
+		if i == 0 {
+			conjVec = z.Copy()
+		} else {
+			conjVec.Scale(residualDot / lastResidualDot).Add(z)
+		}
+		lastResidualDot = residualDot
+		if allZero(conjVec) {
+			break
+		}
+		appliedConj := t.Apply(conjVec)
+		optimalDistance := residualDot / conjVec.Dot(appliedConj)
+
+		solution.AddScaled(conjVec, optimalDistance)
+		if i != 0 && (i%residualUpdateFrequency) == 0 {
+			copy(residual, b)
+			residual.AddScaled(t.Apply(solution), -1)
+		} else {
+			residual.AddScaled(appliedConj, -optimalDistance)
+		}
+
+		select {
+		case <-cancelChan:
+			return solution
+		default:
+		}
+	}
+
+	return solution
+}
+
+// SolvePrec32 is like SolveStoppable32, but it does
+// not give you the option to cancel the solve early.
+func SolvePrec32(t, precond LinTran32, b Vector32, prec float32) Vector32 {
+	return SolveStoppable32(t, precond, b, prec, nil)
+}
+
+func allZero(v Vector32) bool {
+	for _, x := range v {
+		if x != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package conjgrad32
+
+import (
+	"math"
+	"testing"
+)
+
+type diagTran Vector32
+
+func (d diagTran) Dim() int {
+	return len(d)
+}
+
+func (d diagTran) Apply(v Vector32) Vector32 {
+	res := make(Vector32, len(v))
+	for i, x := range v {
+		res[i] = x * d[i]
+	}
+	return res
+}
+
+type tridiagTran int
+
+func (t tridiagTran) Dim() int {
+	return int(t)
+}
+
+func (t tridiagTran) Apply(v Vector32) Vector32 {
+	res := make(Vector32, len(v))
+	for i, x := range v {
+		res[i] = 2 * x
+		if i > 0 {
+			res[i] -= v[i-1]
+		}
+		if i+1 < len(v) {
+			res[i] -= v[i+1]
+		}
+	}
+	return res
+}
+
+func TestSolvePrec32(t *testing.T) {
+	op := tridiagTran(4)
+	b := Vector32{1, 0, 0, 1}
+	for _, precond := range []LinTran32{nil, diagTran{0.5, 0.5, 0.5, 0.5}} {
+		solution := SolvePrec32(op, precond, b, 1e-5)
+		for i, x := range solution {
+			if math.Abs(float64(x-1)) > 1e-4 {
+				t.Errorf("entry %d should be 1 but got %f", i, x)
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package conjgrad32
+
+// Vector32 is a single-precision vector.
+type Vector32 []float32
+
+// Dot returns the dot product of two vectors.
+// The dimensions of v and v1 must match.
+//
+// Like linalg.Vector.Dot, this uses compensated
+// summation, but all arithmetic is done in single
+// precision.
+func (v Vector32) Dot(v1 Vector32) float32 {
+	if len(v) != len(v1) {
+		panic("dimension mismatch")
+	}
+	var sum, compensation float32
+	for i, x := range v {
+		n := x*v1[i] - compensation
+		newSum := sum + n
+		compensation = (newSum - sum) - n
+		sum = newSum
+	}
+	return sum
+}
+
+// Copy returns a copy of this vector.
+func (v Vector32) Copy() Vector32 {
+	res := make(Vector32, len(v))
+	copy(res, v)
+	return res
+}
+
+// Scale scales v in place and returns v.
+func (v Vector32) Scale(c float32) Vector32 {
+	for i, x := range v {
+		v[i] = x * c
+	}
+	return v
+}
+
+// Add adds v1 to v in place and returns v.
+func (v Vector32) Add(v1 Vector32) Vector32 {
+	for i, x := range v1 {
+		v[i] += x
+	}
+	return v
+}
+
+// AddScaled adds s*v1 to v in place and returns v.
+func (v Vector32) AddScaled(v1 Vector32, s float32) Vector32 {
+	for i, x := range v1 {
+		v[i] += s * x
+	}
+	return v
+}
+
+// MaxAbs returns the max of the absolute values
+// of every component in the vector.
+func (v Vector32) MaxAbs() float32 {
+	var res float32
+	for _, x := range v {
+		if x < 0 {
+			x = -x
+		}
+		if x > res {
+			res = x
+		}
+	}
+	return res
+}
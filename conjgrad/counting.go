@@ -0,0 +1,150 @@
+package conjgrad
+
+import (
+	"sync/atomic"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// A CountingTran wraps a LinTran and counts how many
+// times it is applied.
+//
+// Since solvers differ in how many products they need
+// per iteration, the count is a fairer measure of work
+// than the number of iterations.
+// The counter is updated atomically, so a CountingTran
+// may be used by solvers which call Apply from several
+// goroutines at once.
+type CountingTran interface {
+	LinTran
+
+	// ApplyCount returns the number of calls to Apply,
+	// ApplyInto and ApplyTranspose since the
+	// CountingTran was created or last reset.
+	ApplyCount() int
+
+	// Reset sets the count back to zero.
+	Reset()
+}
+
+// NewCountingTran creates a CountingTran which wraps
+// t, starting with a count of zero.
+//
+// The result is a TransposableLinTran if and only if
+// t is, so solvers which check for ApplyTranspose see
+// the same capabilities as they would for t.
+// It is always a LinTranInPlace, and it exposes the
+// entries of *DenseMatrix, *SparseCSR and *SymBand
+// operators, so solvers follow the same code paths as
+// they do for t itself.
+func NewCountingTran(t LinTran) CountingTran {
+	base := &countingTran{t: t}
+	_, transposable := t.(TransposableLinTran)
+	_, rows := t.(rowTran)
+	switch {
+	case transposable && rows:
+		return countingRowTransposable{countingTransposable{base}}
+	case transposable:
+		return countingTransposable{base}
+	case rows:
+		return countingRowTran{base}
+	default:
+		return base
+	}
+}
+
+type countingTran struct {
+	t     LinTran
+	count int64
+}
+
+func (c *countingTran) Dim() int {
+	return c.t.Dim()
+}
+
+func (c *countingTran) Apply(v linalg.Vector) linalg.Vector {
+	atomic.AddInt64(&c.count, 1)
+	return c.t.Apply(v)
+}
+
+func (c *countingTran) ApplyInto(dst, src linalg.Vector) {
+	atomic.AddInt64(&c.count, 1)
+	if inPlace, ok := c.t.(LinTranInPlace); ok {
+		inPlace.ApplyInto(dst, src)
+	} else {
+		copy(dst, c.t.Apply(src))
+	}
+}
+
+func (c *countingTran) ApplyCount() int {
+	return int(atomic.LoadInt64(&c.count))
+}
+
+func (c *countingTran) Reset() {
+	atomic.StoreInt64(&c.count, 0)
+}
+
+type countingTransposable struct {
+	*countingTran
+}
+
+func (c countingTransposable) ApplyTranspose(v linalg.Vector) linalg.Vector {
+	atomic.AddInt64(&c.count, 1)
+	return c.t.(TransposableLinTran).ApplyTranspose(v)
+}
+
+type countingRowTran struct {
+	*countingTran
+}
+
+func (c countingRowTran) iterRow(row int, f func(col int, val float64)) {
+	c.t.(rowTran).iterRow(row, f)
+}
+
+type countingRowTransposable struct {
+	countingTransposable
+}
+
+func (c countingRowTransposable) iterRow(row int, f func(col int, val float64)) {
+	c.t.(rowTran).iterRow(row, f)
+}
+
+// A CountingPreconditioner wraps a Preconditioner and
+// counts how many times it is applied.
+//
+// Together with a CountingTran, this separates the
+// cost of operator applications from the cost of
+// preconditioner applications, which may differ
+// greatly between preconditioners.
+// Like CountingTran, the counter is updated
+// atomically.
+type CountingPreconditioner struct {
+	p     Preconditioner
+	count int64
+}
+
+// NewCountingPreconditioner creates a
+// CountingPreconditioner which wraps p, starting with
+// a count of zero.
+func NewCountingPreconditioner(p Preconditioner) *CountingPreconditioner {
+	return &CountingPreconditioner{p: p}
+}
+
+// ApplyInverse applies the wrapped preconditioner and
+// increments the count.
+func (c *CountingPreconditioner) ApplyInverse(r linalg.Vector) linalg.Vector {
+	atomic.AddInt64(&c.count, 1)
+	return c.p.ApplyInverse(r)
+}
+
+// ApplyCount returns the number of calls to
+// ApplyInverse since the CountingPreconditioner was
+// created or last reset.
+func (c *CountingPreconditioner) ApplyCount() int {
+	return int(atomic.LoadInt64(&c.count))
+}
+
+// Reset sets the count back to zero.
+func (c *CountingPreconditioner) Reset() {
+	atomic.StoreInt64(&c.count, 0)
+}
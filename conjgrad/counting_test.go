@@ -0,0 +1,82 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestCountingTran(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	counter := NewCountingTran(lt)
+	res := SolveDetailed(counter, b, 1e-8)
+	checkSolution(t, res.Solution, realSolution)
+	if counter.ApplyCount() < res.Iterations {
+		t.Errorf("expected at least %d applies but got %d", res.Iterations,
+			counter.ApplyCount())
+	}
+
+	counter.Reset()
+	if counter.ApplyCount() != 0 {
+		t.Error("count was not reset")
+	}
+	counter.(TransposableLinTran).ApplyTranspose(b)
+
+	bs := make([]linalg.Vector, 8)
+	for i := range bs {
+		bs[i] = b
+	}
+	applyAll(counter, bs, 4)
+	if counter.ApplyCount() != 9 {
+		t.Errorf("expected 9 applies but got %d", counter.ApplyCount())
+	}
+}
+
+func TestCountingTranCapabilities(t *testing.T) {
+	band, b := lowMemProblem(50)
+	counter := NewCountingTran(band)
+	if _, ok := counter.(TransposableLinTran); ok {
+		t.Error("wrapper of a non-transposable operator is transposable")
+	}
+	if _, err := SolveNormalEquations(counter, b, 1e-8); err != ErrNotTransposable {
+		t.Errorf("expected ErrNotTransposable but got %v", err)
+	}
+	if _, ok := counter.(rowTran); !ok {
+		t.Error("wrapper should expose the entries of a *SymBand")
+	}
+	NewSSORPreconditioner(counter, 1)
+
+	allocs := testing.AllocsPerRun(5, func() {
+		SolveLowMem(counter, b, 1e-10)
+	})
+	if allocs > 4 {
+		t.Errorf("expected 4 allocations but got %v", allocs)
+	}
+
+	funcTran := NewCountingTran(NewFuncTran(2, func(v linalg.Vector) linalg.Vector {
+		return v.Copy()
+	}))
+	if _, ok := funcTran.(rowTran); ok {
+		t.Error("wrapper of a function should not expose entries")
+	}
+	dst := make(linalg.Vector, 2)
+	funcTran.(LinTranInPlace).ApplyInto(dst, linalg.Vector{1, 2})
+	if dst[0] != 1 || dst[1] != 2 || funcTran.ApplyCount() != 1 {
+		t.Error("unexpected ApplyInto result", dst, funcTran.ApplyCount())
+	}
+}
+
+func TestCountingPreconditioner(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	counter := NewCountingPreconditioner(NewJacobiFromLinTran(lt))
+	res := SolveWith(lt, b, SolveOptions{Tolerance: 1e-8, Preconditioner: counter})
+	checkSolution(t, res.Solution, realSolution)
+	if counter.ApplyCount() != res.Iterations {
+		t.Errorf("expected %d applications but got %d", res.Iterations,
+			counter.ApplyCount())
+	}
+	counter.Reset()
+	if counter.ApplyCount() != 0 {
+		t.Error("count was not reset")
+	}
+}
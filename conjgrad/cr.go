@@ -0,0 +1,49 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// SolveCR solves t*x = b for a symmetric (but not
+// necessarily positive-definite) t using the conjugate
+// residual method.
+//
+// CR minimizes the 2-norm of the residual over each
+// Krylov subspace, so the residual norm decreases
+// monotonically.
+// It needs one t.Apply per iteration, like CG, but it
+// stores two extra vectors.
+//
+// The solve stops when no component of the residual
+// exceeds prec, or when cancelChan is closed.
+func SolveCR(t LinTran, b linalg.Vector, prec float64,
+	cancelChan <-chan struct{}) linalg.Vector {
+	solution := make(linalg.Vector, t.Dim())
+	residual := b.Copy()
+	appliedResidual := t.Apply(residual)
+	conjVec := residual.Copy()
+	appliedConj := appliedResidual.Copy()
+	lastResidualDot := residual.Dot(appliedResidual)
+
+	for iters := 0; residual.NormInf() > prec; iters++ {
+		appliedDot := appliedConj.Dot(appliedConj)
+		if appliedDot == 0 || lastResidualDot == 0 {
+			break
+		}
+		step := lastResidualDot / appliedDot
+		solution.AddScaled(conjVec, step)
+		updateResidual(t, b, solution, residual, appliedConj, step,
+			iters, residualUpdateFrequency, nil)
+
+		appliedResidual = t.Apply(residual)
+		residualDot := residual.Dot(appliedResidual)
+		beta := residualDot / lastResidualDot
+		conjVec.Scale(beta).Add(residual)
+		appliedConj.Scale(beta).Add(appliedResidual)
+		lastResidualDot = residualDot
+
+		if cancelled(cancelChan) {
+			return solution
+		}
+	}
+
+	return solution
+}
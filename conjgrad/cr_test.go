@@ -0,0 +1,13 @@
+package conjgrad
+
+import "testing"
+
+func TestSolveCR(t *testing.T) {
+	lt, b, realSolution := indefiniteProblem()
+	solution := SolveCR(lt, b, 1e-10, nil)
+	checkSolution(t, solution, realSolution)
+
+	lt1, b1, realSolution1 := testProblem()
+	solution = SolveCR(lt1, b1, 1e-9, nil)
+	checkSolution(t, solution, realSolution1)
+}
@@ -0,0 +1,116 @@
+package conjgrad
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// ReadMatrixCSV reads a dense matrix with one
+// comma-separated row per line.
+//
+// An error is returned if the input is empty, if any
+// entry is not a number, or if the rows have different
+// lengths.
+func ReadMatrixCSV(r io.Reader) (*DenseMatrix, error) {
+	records, err := readCSVFloats(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("csv: empty matrix")
+	}
+	res := &DenseMatrix{Rows: len(records), Cols: len(records[0].values)}
+	for _, record := range records {
+		if len(record.values) != res.Cols {
+			return nil, fmt.Errorf("csv: line %d: expected %d entries but got %d",
+				record.line, res.Cols, len(record.values))
+		}
+		res.Data = append(res.Data, record.values...)
+	}
+	return res, nil
+}
+
+// ReadVectorCSV reads a vector with one entry per
+// line.
+func ReadVectorCSV(r io.Reader) (linalg.Vector, error) {
+	records, err := readCSVFloats(r)
+	if err != nil {
+		return nil, err
+	}
+	res := make(linalg.Vector, len(records))
+	for i, record := range records {
+		if len(record.values) != 1 {
+			return nil, fmt.Errorf("csv: line %d: expected 1 entry but got %d",
+				record.line, len(record.values))
+		}
+		res[i] = record.values[0]
+	}
+	return res, nil
+}
+
+// WriteMatrixCSV writes a matrix in the format read
+// by ReadMatrixCSV.
+// Entries are written with enough digits to be read
+// back exactly.
+func WriteMatrixCSV(w io.Writer, m *DenseMatrix) error {
+	writer := csv.NewWriter(w)
+	row := make([]string, m.Cols)
+	for i := 0; i < m.Rows; i++ {
+		for j := range row {
+			row[j] = strconv.FormatFloat(m.At(i, j), 'g', -1, 64)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteVectorCSV writes a vector in the format read
+// by ReadVectorCSV.
+func WriteVectorCSV(w io.Writer, v linalg.Vector) error {
+	writer := csv.NewWriter(w)
+	for _, x := range v {
+		if err := writer.Write([]string{strconv.FormatFloat(x, 'g', -1, 64)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+type csvRecord struct {
+	line   int
+	values []float64
+}
+
+func readCSVFloats(r io.Reader) ([]csvRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	var res []csvRecord
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			return res, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("csv: %v", err)
+		}
+		line, _ := reader.FieldPos(0)
+		record := csvRecord{line: line, values: make([]float64, len(fields))}
+		for i, field := range fields {
+			x, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return nil, fmt.Errorf("csv: line %d: invalid entry %q", line, field)
+			}
+			record.values[i] = x
+		}
+		res = append(res, record)
+	}
+}
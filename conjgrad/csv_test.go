@@ -0,0 +1,63 @@
+package conjgrad
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestMatrixCSV(t *testing.T) {
+	mat, err := ReadMatrixCSV(strings.NewReader("1,2,3\n4, 5, 6.5\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mat.Rows != 2 || mat.Cols != 3 || mat.At(1, 2) != 6.5 {
+		t.Fatalf("unexpected matrix %+v", mat)
+	}
+
+	var buf bytes.Buffer
+	mat.Set(0, 0, 1.0/3)
+	if err := WriteMatrixCSV(&buf, mat); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ReadMatrixCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, x := range mat.Data {
+		if decoded.Data[i] != x {
+			t.Errorf("entry %d: expected %v but got %v", i, x, decoded.Data[i])
+		}
+	}
+
+	badInputs := []string{"", "1,2\n3\n", "1,x\n"}
+	for _, input := range badInputs {
+		if _, err := ReadMatrixCSV(strings.NewReader(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+	_, err = ReadMatrixCSV(strings.NewReader("1,2\n3,4\n5\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("expected error on line 3 but got %v", err)
+	}
+}
+
+func TestVectorCSV(t *testing.T) {
+	vec := linalg.Vector{1, -2.5, 1.0 / 7}
+	var buf bytes.Buffer
+	if err := WriteVectorCSV(&buf, vec); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ReadVectorCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.ApproxEqual(vec, 0) {
+		t.Errorf("expected %v but got %v", vec, decoded)
+	}
+	if _, err := ReadVectorCSV(strings.NewReader("1\n2,3\n")); err == nil {
+		t.Error("expected error for multiple entries on a line")
+	}
+}
@@ -0,0 +1,138 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// SolveDeflated solves the symmetric positive-definite
+// system t*x = b using CG with the span of deflationVecs
+// projected out of the iteration.
+//
+// The deflation vectors are orthonormalized first, and
+// vectors which depend linearly on earlier ones are
+// dropped.
+// The component of the solution in their span is found
+// exactly with a small dense solve, while CG works on
+// the rest.
+// When the vectors approximate the eigenvectors for
+// the smallest eigenvalues of t, those eigenvalues no
+// longer slow CG down.
+//
+// Poor deflation vectors make convergence slower but
+// do not affect the correctness of the solution.
+// Deflation also composes with preconditioning; see
+// SolveDeflatedPreconditioned.
+//
+// The solve stops when no component of the residual
+// of the final solution exceeds prec.
+func SolveDeflated(t LinTran, b linalg.Vector, deflationVecs []linalg.Vector,
+	prec float64) linalg.Vector {
+	return SolveDeflatedPreconditioned(t, nil, b, deflationVecs, prec)
+}
+
+// SolveDeflatedPreconditioned is like SolveDeflated,
+// but the CG iteration on the deflated system uses the
+// preconditioner m.
+//
+// If m is nil, then no preconditioning is used.
+func SolveDeflatedPreconditioned(t LinTran, m Preconditioner, b linalg.Vector,
+	deflationVecs []linalg.Vector, prec float64) linalg.Vector {
+	return SolveDeflatedStoppable(t, m, b, deflationVecs, prec, nil)
+}
+
+// SolveDeflatedStoppable is like
+// SolveDeflatedPreconditioned, but it stops the inner
+// CG iteration once cancelChan is closed and returns
+// the corresponding solution.
+func SolveDeflatedStoppable(t LinTran, m Preconditioner, b linalg.Vector,
+	deflationVecs []linalg.Vector, prec float64, cancelChan <-chan struct{}) linalg.Vector {
+	d := newDeflatedTran(t, deflationVecs)
+	if d == nil {
+		return SolvePreconditioned(t, m, b, prec, cancelChan)
+	}
+	inner := SolvePreconditioned(d, m, d.project(b), prec, cancelChan)
+	return d.coarseSolve(b).Add(d.projectTranspose(inner))
+}
+
+// deflatedTran is the LinTran P*A, where
+// P = I - A*W*E^-1*W' and E = W'*A*W for an
+// orthonormal basis W.
+type deflatedTran struct {
+	t       LinTran
+	basis   []linalg.Vector
+	applied []linalg.Vector
+	coarse  *CholeskyFactor
+}
+
+// newDeflatedTran orthonormalizes the vectors and
+// builds the deflated operator.
+// It returns nil if no independent vectors remain.
+func newDeflatedTran(t LinTran, vecs []linalg.Vector) *deflatedTran {
+	for _, v := range vecs {
+		if len(v) != t.Dim() {
+			panic("dimension mismatch")
+		}
+	}
+	basis := Orthonormalize(vecs)
+	if len(basis) == 0 {
+		return nil
+	}
+
+	applied := make([]linalg.Vector, len(basis))
+	coarse := NewDenseMatrix(len(basis), len(basis))
+	for i, q := range basis {
+		applied[i] = t.Apply(q)
+		for j := 0; j <= i; j++ {
+			coarse.SetSym(i, j, basis[j].Dot(applied[i]))
+		}
+	}
+	factor, err := Cholesky(coarse)
+	if err != nil {
+		panic("operator is not positive-definite")
+	}
+	return &deflatedTran{t: t, basis: basis, applied: applied, coarse: factor}
+}
+
+func (d *deflatedTran) Dim() int {
+	return d.t.Dim()
+}
+
+func (d *deflatedTran) Apply(v linalg.Vector) linalg.Vector {
+	return d.project(d.t.Apply(v))
+}
+
+// coarseSolve computes W*E^-1*W'*v.
+func (d *deflatedTran) coarseSolve(v linalg.Vector) linalg.Vector {
+	coeffs := d.coarse.Solve(dotAll(d.basis, v))
+	res := make(linalg.Vector, len(v))
+	for i, q := range d.basis {
+		res.AddScaled(q, coeffs[i])
+	}
+	return res
+}
+
+// project computes P*v.
+func (d *deflatedTran) project(v linalg.Vector) linalg.Vector {
+	coeffs := d.coarse.Solve(dotAll(d.basis, v))
+	res := v.Copy()
+	for i, av := range d.applied {
+		res.AddScaled(av, -coeffs[i])
+	}
+	return res
+}
+
+// projectTranspose computes P'*v.
+func (d *deflatedTran) projectTranspose(v linalg.Vector) linalg.Vector {
+	coeffs := d.coarse.Solve(dotAll(d.applied, v))
+	res := v.Copy()
+	for i, q := range d.basis {
+		res.AddScaled(q, -coeffs[i])
+	}
+	return res
+}
+
+func dotAll(vecs []linalg.Vector, v linalg.Vector) linalg.Vector {
+	res := make(linalg.Vector, len(vecs))
+	for i, vec := range vecs {
+		res[i] = vec.Dot(v)
+	}
+	return res
+}
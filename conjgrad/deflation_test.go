@@ -0,0 +1,40 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveDeflated(t *testing.T) {
+	diag := make(Diagonal, 20)
+	for i := range diag {
+		diag[i] = float64(i + 1)
+	}
+	diag[3], diag[7] = 1e-6, 1e-5
+	expected := linalg.RandVector(20)
+	b := diag.Apply(expected)
+
+	near := make(linalg.Vector, 20)
+	near[3] = 1
+	other := make(linalg.Vector, 20)
+	other[7] = 2
+	other[3] = 1
+	solution := SolveDeflated(diag, b, []linalg.Vector{near, other, near.Copy().Scale(3)}, 1e-12)
+	checkSolution(t, solution, expected)
+
+	// Unrelated vectors must not break the solve.
+	solution = SolveDeflated(diag, b, []linalg.Vector{linalg.RandVector(20)}, 1e-12)
+	checkSolution(t, solution, expected)
+
+	lt, b, realSolution := testProblem()
+	solution = SolveDeflated(lt, b, []linalg.Vector{linalg.RandVector(5), linalg.RandVector(5)}, 1e-8)
+	checkSolution(t, solution, realSolution)
+}
+
+func TestSolveDeflatedPreconditioned(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	m := NewJacobiFromLinTran(lt)
+	solution := SolveDeflatedPreconditioned(lt, m, b, []linalg.Vector{linalg.RandVector(5)}, 1e-8)
+	checkSolution(t, solution, realSolution)
+}
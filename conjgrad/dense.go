@@ -0,0 +1,163 @@
+package conjgrad
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// DenseMatrix is a LinTran backed by an explicit
+// matrix whose entries are stored in row-major order.
+//
+// A DenseMatrix is only a valid LinTran when it is
+// square, in which case Dim() is the number of rows.
+type DenseMatrix struct {
+	Rows int
+	Cols int
+
+	// Data is ordered from left to right, top
+	// to bottom.
+	Data []float64
+}
+
+// NewDenseMatrix creates a matrix of a given size
+// with zeroes in every entry.
+func NewDenseMatrix(rows, cols int) *DenseMatrix {
+	return &DenseMatrix{
+		Rows: rows,
+		Cols: cols,
+		Data: make([]float64, rows*cols),
+	}
+}
+
+// At returns the entry at the i-th row and j-th
+// column, where i and j start at 0.
+func (d *DenseMatrix) At(i, j int) float64 {
+	return d.Data[i*d.Cols+j]
+}
+
+// Set updates the entry at the i-th row and j-th
+// column.
+func (d *DenseMatrix) Set(i, j int, v float64) {
+	d.Data[i*d.Cols+j] = v
+}
+
+// SetSym sets the entries at (i, j) and (j, i) to v,
+// keeping a symmetric matrix symmetric.
+func (d *DenseMatrix) SetSym(i, j int, v float64) {
+	d.Set(i, j, v)
+	d.Set(j, i, v)
+}
+
+// SymmetrizeUpper copies the upper triangle of the
+// matrix into its lower triangle, so that the matrix
+// becomes symmetric.
+//
+// This panics if the matrix is not square.
+func (d *DenseMatrix) SymmetrizeUpper() {
+	if d.Rows != d.Cols {
+		panic("matrix is not square")
+	}
+	for i := 0; i < d.Rows; i++ {
+		for j := i + 1; j < d.Cols; j++ {
+			d.Set(j, i, d.At(i, j))
+		}
+	}
+}
+
+// Dim returns the number of rows in the matrix.
+func (d *DenseMatrix) Dim() int {
+	return d.Rows
+}
+
+// Apply returns the product of the matrix and v.
+//
+// The length of v must match d.Cols.
+func (d *DenseMatrix) Apply(v linalg.Vector) linalg.Vector {
+	res := make(linalg.Vector, d.Rows)
+	d.ApplyInto(res, v)
+	return res
+}
+
+// ApplyInto stores the product of the matrix and src
+// in dst.
+func (d *DenseMatrix) ApplyInto(dst, src linalg.Vector) {
+	if len(src) != d.Cols || len(dst) != d.Rows {
+		panic("dimension mismatch")
+	}
+	for i := range dst {
+		row := linalg.Vector(d.Data[i*d.Cols : (i+1)*d.Cols])
+		dst[i] = row.DotFast(src)
+	}
+}
+
+// ApplyTranspose returns the product of the transpose
+// of the matrix and v.
+//
+// The length of v must match d.Rows.
+func (d *DenseMatrix) ApplyTranspose(v linalg.Vector) linalg.Vector {
+	if len(v) != d.Rows {
+		panic("dimension mismatch")
+	}
+	res := make(linalg.Vector, d.Cols)
+	for i, x := range v {
+		row := linalg.Vector(d.Data[i*d.Cols : (i+1)*d.Cols])
+		res.AddScaled(row, x)
+	}
+	return res
+}
+
+// Matrix returns a linalg.Matrix which shares its
+// entries with d.
+func (d *DenseMatrix) Matrix() *linalg.Matrix {
+	return &linalg.Matrix{Rows: d.Rows, Cols: d.Cols, Data: d.Data}
+}
+
+func (d *DenseMatrix) iterRow(row int, f func(col int, val float64)) {
+	for col, val := range d.Data[row*d.Cols : (row+1)*d.Cols] {
+		if val != 0 {
+			f(col, val)
+		}
+	}
+}
+
+// GobEncode encodes the matrix, including its
+// dimensions, in a compact binary form which
+// preserves every entry exactly.
+func (d *DenseMatrix) GobEncode() ([]byte, error) {
+	data, err := linalg.Vector(d.Data).GobEncode()
+	if err != nil {
+		return nil, err
+	}
+	res := binary.AppendUvarint(nil, uint64(d.Rows))
+	res = binary.AppendUvarint(res, uint64(d.Cols))
+	return append(res, data...), nil
+}
+
+// GobDecode decodes a matrix which was encoded by
+// GobEncode.
+func (d *DenseMatrix) GobDecode(data []byte) error {
+	rows, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errors.New("invalid matrix rows")
+	}
+	data = data[n:]
+	cols, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errors.New("invalid matrix columns")
+	}
+	var entries linalg.Vector
+	if err := entries.GobDecode(data[n:]); err != nil {
+		return err
+	}
+	if cols != 0 && rows > uint64(len(entries))/cols || rows*cols != uint64(len(entries)) ||
+		rows > math.MaxInt || cols > math.MaxInt {
+		return errors.New("matrix dimensions do not match data length")
+	}
+	d.Rows = int(rows)
+	d.Cols = int(cols)
+	d.Data = entries
+	return nil
+}
@@ -0,0 +1,86 @@
+package conjgrad
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestDenseMatrix(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	mat := NewDenseMatrix(5, 5)
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			mat.Set(i, j, lt.M.Get(i, j))
+		}
+	}
+	if mat.At(1, 2) != lt.M.Get(1, 2) {
+		t.Error("unexpected entry", mat.At(1, 2))
+	}
+	checkSolution(t, SolvePrec(mat, nil, b, 1e-8), realSolution)
+
+	rect := NewDenseMatrix(2, 3)
+	copy(rect.Data, []float64{1, 2, 3, 4, 5, 6})
+	checkSolution(t, rect.Apply(linalg.Vector{1, 0, -1}), linalg.Vector{-2, -2})
+}
+
+func TestDenseMatrixSymmetric(t *testing.T) {
+	d := NewDenseMatrix(3, 3)
+	d.SetSym(0, 2, 5)
+	if d.At(0, 2) != 5 || d.At(2, 0) != 5 {
+		t.Error("SetSym did not set both entries")
+	}
+
+	d = &DenseMatrix{Rows: 3, Cols: 3, Data: []float64{
+		1, 2, 3,
+		0, 4, 5,
+		9, 9, 6,
+	}}
+	d.SymmetrizeUpper()
+	expected := []float64{
+		1, 2, 3,
+		2, 4, 5,
+		3, 5, 6,
+	}
+	checkSolution(t, linalg.Vector(d.Data), expected)
+}
+
+func TestDenseMatrixGob(t *testing.T) {
+	lt, b, _ := testProblem()
+	mat := &DenseMatrix{Rows: 5, Cols: 5, Data: lt.M.Data}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(mat); err != nil {
+		t.Fatal(err)
+	}
+	var decoded DenseMatrix
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Rows != mat.Rows || decoded.Cols != mat.Cols {
+		t.Fatalf("unexpected dimensions %dx%d", decoded.Rows, decoded.Cols)
+	}
+	expected := mat.Apply(b)
+	actual := decoded.Apply(b)
+	for i, x := range expected {
+		if x != actual[i] {
+			t.Errorf("index %d: expected %v but got %v", i, x, actual[i])
+		}
+	}
+}
+
+func TestDenseMatrixGobInvalid(t *testing.T) {
+	entries, _ := linalg.Vector{1, 2}.GobEncode()
+	for _, dims := range [][2]uint64{{1 << 32, 1 << 32}, {1 << 63, 0}, {3, 1}} {
+		data := binary.AppendUvarint(nil, dims[0])
+		data = binary.AppendUvarint(data, dims[1])
+		data = append(data, entries...)
+		var m DenseMatrix
+		if err := m.GobDecode(data); err == nil {
+			t.Errorf("expected error for dimensions %v", dims)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// Diagonal is a LinTran which represents a diagonal
+// matrix, given its diagonal entries.
+type Diagonal linalg.Vector
+
+// Dim returns the number of diagonal entries.
+func (d Diagonal) Dim() int {
+	return len(d)
+}
+
+// Apply multiplies each component of v by the
+// corresponding diagonal entry.
+func (d Diagonal) Apply(v linalg.Vector) linalg.Vector {
+	if len(v) != len(d) {
+		panic("dimension mismatch")
+	}
+	res := make(linalg.Vector, len(v))
+	for i, x := range v {
+		res[i] = x * d[i]
+	}
+	return res
+}
+
+// Inverse returns the inverse of the diagonal
+// matrix, which is itself a Diagonal.
+//
+// This panics if any diagonal entry is zero.
+func (d Diagonal) Inverse() LinTran {
+	res := make(Diagonal, len(d))
+	for i, x := range d {
+		if x == 0 {
+			panic("singular diagonal matrix")
+		}
+		res[i] = 1 / x
+	}
+	return res
+}
@@ -0,0 +1,148 @@
+package conjgrad
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// SolveEisenstatSSOR solves the symmetric positive-
+// definite system a*x = b using SSOR-preconditioned CG
+// with the relaxation factor omega, applying the
+// preconditioner with the Eisenstat trick.
+//
+// Write a = L + D + L' and D' = D/omega.
+// CG runs on the split-preconditioned operator
+//
+//	S*(D'+L)^-1 * a * (D'+L')^-1*S
+//
+// where S = sqrt(D'), and since a is the sum of D'+L,
+// D'+L' and D-2*D', each application of this operator
+// needs just one forward and one backward triangular
+// sweep.
+// Standard SSOR-CG needs the same two sweeps plus a
+// full product with a.
+// Checking the true residual costs one more product
+// with the lower triangle, half of a full product.
+//
+// As with NewSSORPreconditioner, a must expose its
+// entries, so it must be a *DenseMatrix, *SparseCSR,
+// or *SymBand.
+// This panics if omega is not in the open interval
+// (0, 2), if a is a matrix-free LinTran, or if any
+// diagonal entry of a is zero.
+//
+// The solve stops when no component of the residual
+// of the original system exceeds prec.
+func SolveEisenstatSSOR(a LinTran, omega, prec float64, b linalg.Vector) linalg.Vector {
+	return SolveEisenstatSSORStoppable(a, omega, prec, b, nil)
+}
+
+// SolveEisenstatSSORStoppable is like
+// SolveEisenstatSSOR, but it returns the current
+// solution once cancelChan is closed.
+func SolveEisenstatSSORStoppable(a LinTran, omega, prec float64, b linalg.Vector,
+	cancelChan <-chan struct{}) linalg.Vector {
+	s := NewSSORPreconditioner(a, omega).(*ssorPreconditioner)
+	if len(b) != len(s.diagonal) {
+		panic("dimension mismatch")
+	}
+	scale := make(linalg.Vector, len(b))
+	for i, d := range s.diagonal {
+		if !(d > 0) {
+			panic(fmt.Sprintf("non-positive diagonal entry at index %d", i))
+		}
+		scale[i] = math.Sqrt(d / omega)
+	}
+
+	apply := func(v linalg.Vector) linalg.Vector {
+		v = elementwiseProduct(scale, v)
+		upper := s.upperSolve(v)
+		// K = D - 2*D' is the rest of a.
+		for i, d := range s.diagonal {
+			v[i] += (d - 2*d/omega) * upper[i]
+		}
+		return elementwiseProduct(scale, s.lowerSolve(v).Add(upper))
+	}
+
+	solution := make(linalg.Vector, len(b))
+	residual := elementwiseProduct(scale, s.lowerSolve(b))
+	conjVec := residual.Copy()
+	residualDot := residual.Dot(residual)
+	trueResidual := b.Copy()
+	for trueResidual.NormInf() > prec && !cancelled(cancelChan) {
+		applied := apply(conjVec)
+		curvature := conjVec.Dot(applied)
+		if !(curvature > 0) {
+			break
+		}
+		step := residualDot / curvature
+		solution.AddScaled(conjVec, step)
+		residual.AddScaled(applied, -step)
+
+		unscaled := residual.Copy()
+		for i, x := range scale {
+			unscaled[i] /= x
+		}
+		trueResidual = s.lowerMul(unscaled)
+
+		newResidualDot := residual.Dot(residual)
+		conjVec.Scale(newResidualDot / residualDot).Add(residual)
+		residualDot = newResidualDot
+	}
+	return s.upperSolve(elementwiseProduct(scale, solution))
+}
+
+// lowerSolve solves (D/omega + L)y = v.
+func (s *ssorPreconditioner) lowerSolve(v linalg.Vector) linalg.Vector {
+	res := make(linalg.Vector, len(v))
+	for row := range res {
+		sum := v[row]
+		s.a.iterRow(row, func(col int, val float64) {
+			if col < row {
+				sum -= val * res[col]
+			}
+		})
+		res[row] = sum * s.omega / s.diagonal[row]
+	}
+	return res
+}
+
+// upperSolve solves (D/omega + U)y = v.
+func (s *ssorPreconditioner) upperSolve(v linalg.Vector) linalg.Vector {
+	res := make(linalg.Vector, len(v))
+	for row := len(res) - 1; row >= 0; row-- {
+		sum := v[row]
+		s.a.iterRow(row, func(col int, val float64) {
+			if col > row {
+				sum -= val * res[col]
+			}
+		})
+		res[row] = sum * s.omega / s.diagonal[row]
+	}
+	return res
+}
+
+// lowerMul computes (D/omega + L)v.
+func (s *ssorPreconditioner) lowerMul(v linalg.Vector) linalg.Vector {
+	res := make(linalg.Vector, len(v))
+	for row := range res {
+		sum := v[row] * s.diagonal[row] / s.omega
+		s.a.iterRow(row, func(col int, val float64) {
+			if col < row {
+				sum += val * v[col]
+			}
+		})
+		res[row] = sum
+	}
+	return res
+}
+
+func elementwiseProduct(v, v1 linalg.Vector) linalg.Vector {
+	res := make(linalg.Vector, len(v))
+	for i, x := range v {
+		res[i] = x * v1[i]
+	}
+	return res
+}
@@ -0,0 +1,30 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveEisenstatSSOR(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	dense := &DenseMatrix{Rows: 5, Cols: 5, Data: lt.M.Data}
+	for _, omega := range []float64{0.5, 1, 1.5} {
+		solution := SolveEisenstatSSOR(dense, omega, 1e-10, b)
+		checkSolution(t, solution, realSolution)
+	}
+
+	band := NewSymBand(50, 1)
+	for i := 0; i < 50; i++ {
+		band.SetBand(i, 0, 2)
+		if i+1 < 50 {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	expected := linalg.RandVector(50)
+	rhs := band.Apply(expected)
+	solution := SolveEisenstatSSOR(band, 1.7, 1e-10, rhs)
+	if residual := rhs.Sub(band.Apply(solution)).NormInf(); residual > 1e-10 {
+		t.Error("unexpected residual", residual)
+	}
+}
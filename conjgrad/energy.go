@@ -0,0 +1,143 @@
+package conjgrad
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// QuadraticForm evaluates x'*t*x/2 - b'*x, which is
+// the objective that CG minimizes for an SPD t.
+//
+// This calls t.Apply exactly once.
+func QuadraticForm(t LinTran, x, b linalg.Vector) float64 {
+	return 0.5*x.Dot(t.Apply(x)) - b.Dot(x)
+}
+
+// EnergyNorm computes the t-norm sqrt(v'*t*v) of v.
+// CG reduces the t-norm of the error monotonically.
+//
+// This calls t.Apply exactly once.
+// If t is not positive-definite, the result may be
+// NaN.
+func EnergyNorm(t LinTran, v linalg.Vector) float64 {
+	return math.Sqrt(v.Dot(t.Apply(v)))
+}
+
+// errorBoundsDriftTol is the relative distance between
+// the recursive and true residuals beyond which
+// SolveWithErrorBoundsChecked stops trusting its bounds.
+const errorBoundsDriftTol = 1e-3
+
+// SolveWithErrorBounds solves t*x = b with CG for a
+// symmetric positive-definite t, stopping once the
+// t-norm of the error is guaranteed to be no greater
+// than energyTol.
+//
+// The bounds come from Gauss and Gauss-Radau
+// quadrature of the Lanczos process underlying CG,
+// computed from the CG coefficients as described by
+// Meurant and Tichy.
+// The Gauss-Radau upper bound requires
+// lambdaMinLowerBound to be positive and no greater
+// than the smallest eigenvalue of t; a tighter lower
+// bound on the spectrum gives a tighter upper bound on
+// the error.
+//
+// It returns the solution together with a lower and an
+// upper bound on the t-norm of its error.
+// The bounds only hold while the recursively updated
+// residual matches the true residual b-t*x, so the
+// true residual is recomputed every 20 iterations.
+// If the two have drifted apart, or the Gauss-Radau
+// recurrence breaks down, the current solution is
+// returned with an infinite upper bound.
+// The solve gives up after 10*t.Dim() iterations, even
+// if the upper bound is still above energyTol.
+//
+// See SolveWithErrorBoundsChecked for a variant with
+// an explicit iteration limit which reports why the
+// bounds could not be met.
+func SolveWithErrorBounds(t LinTran, b linalg.Vector, lambdaMinLowerBound,
+	energyTol float64) (linalg.Vector, float64, float64) {
+	solution, lower, upper, _ := SolveWithErrorBoundsChecked(t, b, lambdaMinLowerBound,
+		energyTol, 0)
+	return solution, lower, upper
+}
+
+// SolveWithErrorBoundsChecked is like
+// SolveWithErrorBounds, but it stops after maxIter
+// iterations and returns a non-nil error if the upper
+// bound did not reach energyTol.
+// The error says whether the iteration limit was hit,
+// the residuals drifted apart, or the Gauss-Radau
+// recurrence broke down; in the latter two cases, the
+// upper bound is infinite.
+// If maxIter is 0 or negative, 10*t.Dim() is used.
+func SolveWithErrorBoundsChecked(t LinTran, b linalg.Vector, lambdaMinLowerBound,
+	energyTol float64, maxIter int) (solution linalg.Vector, lower, upper float64,
+	err error) {
+	if !(lambdaMinLowerBound > 0) {
+		panic("eigenvalue bound must be positive")
+	}
+	if len(b) != t.Dim() {
+		panic("dimension mismatch")
+	}
+	if maxIter <= 0 {
+		maxIter = defaultMaxIterFactor * t.Dim()
+	}
+	solution = make(linalg.Vector, len(b))
+	residual := b.Copy()
+	conjVec := b.Copy()
+	residualDot := residual.Dot(residual)
+
+	// radauStep is the CG step length which the Radau
+	// tridiagonal matrix, with an extra eigenvalue at
+	// lambdaMinLowerBound, would produce.
+	radauStep := 1 / lambdaMinLowerBound
+
+	for iters := 0; ; iters++ {
+		if residualDot == 0 {
+			return solution, 0, 0, nil
+		}
+		upper = math.Sqrt(radauStep * residualDot)
+		appliedConj := t.Apply(conjVec)
+		step := residualDot / conjVec.Dot(appliedConj)
+		lower = math.Sqrt(step * residualDot)
+		if upper <= energyTol {
+			return
+		}
+		if iters >= maxIter {
+			err = fmt.Errorf("error bound %g not below %g after %d iterations",
+				upper, energyTol, iters)
+			return
+		}
+
+		solution.AddScaled(conjVec, step)
+		residual.AddScaled(appliedConj, -step)
+		if (iters+1)%residualUpdateFrequency == 0 {
+			trueResidual := b.Copy().Sub(t.Apply(solution))
+			drift := trueResidual.Copy().Sub(residual).Mag()
+			if drift > errorBoundsDriftTol*residual.Mag() {
+				err = fmt.Errorf("residual drifted by %g at iteration %d, so the "+
+					"error bounds are unreliable", drift, iters+1)
+				upper = math.Inf(1)
+				return
+			}
+			copy(residual, trueResidual)
+		}
+		nextDot := residual.Dot(residual)
+		beta := nextDot / residualDot
+		conjVec.Scale(beta).Add(residual)
+		residualDot = nextDot
+
+		gap := radauStep - step
+		if !(gap > 0) {
+			err = fmt.Errorf("Gauss-Radau recurrence broke down at iteration %d", iters+1)
+			upper = math.Inf(1)
+			return
+		}
+		radauStep = gap / (lambdaMinLowerBound*gap + beta)
+	}
+}
@@ -0,0 +1,107 @@
+package conjgrad
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestQuadraticForm(t *testing.T) {
+	lt, b, realSolution := testProblem()
+
+	// The minimum value is -b'*x/2 at the solution.
+	minimum := QuadraticForm(lt, realSolution, b)
+	if math.Abs(minimum+b.Dot(realSolution)/2) > 1e-6 {
+		t.Errorf("unexpected minimum %f", minimum)
+	}
+
+	last := QuadraticForm(lt, make(linalg.Vector, len(b)), b)
+	for i := 1; i <= 5; i++ {
+		x := SolveWith(lt, b, SolveOptions{MaxIter: i}).Solution
+		value := QuadraticForm(lt, x, b)
+		if value > last+1e-8 {
+			t.Errorf("objective increased at iteration %d: %f > %f", i, value, last)
+		}
+		last = value
+	}
+}
+
+func TestEnergyNorm(t *testing.T) {
+	d := Diagonal(linalg.Vector{4, 9})
+	if norm := EnergyNorm(d, linalg.Vector{1, 1}); math.Abs(norm-math.Sqrt(13)) > 1e-12 {
+		t.Errorf("expected sqrt(13) but got %f", norm)
+	}
+}
+
+func TestSolveEnergyTol(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution := SolveEnergyTol(lt, b, 1e-8)
+	checkSolution(t, solution, realSolution)
+
+	band := NewSymBand(100, 1)
+	for i := 0; i < 100; i++ {
+		band.SetBand(i, 0, 2.5)
+		if i+1 < 100 {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	expected := linalg.RandVector(100)
+	b = band.Apply(expected)
+	for _, tol := range []float64{1e-2, 1e-5} {
+		solution = SolveEnergyTolDelay(band, b, tol, 3)
+		errNorm := EnergyNorm(band, solution.Copy().Sub(expected))
+		if errNorm > tol*10 {
+			t.Errorf("tolerance %g: true error is %g", tol, errNorm)
+		}
+	}
+}
+
+func TestSolveWithErrorBounds(t *testing.T) {
+	band := NewSymBand(100, 1)
+	for i := 0; i < 100; i++ {
+		band.SetBand(i, 0, 2.5)
+		if i+1 < 100 {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	// The eigenvalues of band lie in (0.5, 4.5).
+	expected := linalg.RandVector(100)
+	b := band.Apply(expected)
+	for _, tol := range []float64{1e-2, 1e-6} {
+		solution, lower, upper := SolveWithErrorBounds(band, b, 0.5, tol)
+		errNorm := EnergyNorm(band, solution.Copy().Sub(expected))
+		if upper > tol {
+			t.Errorf("tolerance %g: upper bound %g", tol, upper)
+		}
+		if errNorm > upper*(1+1e-6) || errNorm < lower*(1-1e-6) {
+			t.Errorf("tolerance %g: error %g not in [%g, %g]", tol, errNorm, lower, upper)
+		}
+	}
+}
+
+func TestSolveWithErrorBoundsUnreliable(t *testing.T) {
+	diag := make(Diagonal, 200)
+	for i := range diag {
+		diag[i] = math.Pow(10, 6*float64(i)/float64(len(diag)-1))
+	}
+	expected := linalg.RandVector(len(diag))
+	b := diag.Apply(expected)
+
+	solution, _, upper, err := SolveWithErrorBoundsChecked(diag, b, 1, 1e-18, 0)
+	errNorm := EnergyNorm(diag, solution.Copy().Sub(expected))
+	if err == nil && errNorm > upper {
+		t.Errorf("error %g exceeds upper bound %g without an error", errNorm, upper)
+	}
+
+	solution, _, upper = SolveWithErrorBounds(diag, b, 1, 1e-18)
+	errNorm = EnergyNorm(diag, solution.Copy().Sub(expected))
+	if errNorm > upper {
+		t.Errorf("error %g exceeds upper bound %g", errNorm, upper)
+	}
+
+	_, _, _, err = SolveWithErrorBoundsChecked(diag, b, 1, 0, 3)
+	if err == nil {
+		t.Error("expected an error after 3 iterations")
+	}
+}
@@ -0,0 +1,82 @@
+package conjgrad
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// Equilibrate scales a symmetric positive-definite a
+// to D^-1/2*a*D^-1/2, where D is the diagonal of a, so
+// that every diagonal entry of the result is 1.
+// This Jacobi equilibration often improves the
+// conditioning of badly scaled problems.
+//
+// The scaled operator wraps a without copying it.
+// The returned dInvSqrt holds the diagonal of D^-1/2.
+// To solve a*x = b with it:
+//
+//  1. Scale b with ScaleRHS(b, dInvSqrt).
+//  2. Solve scaled*y = D^-1/2*b.
+//  3. Recover x with UnscaleSolution(y, dInvSqrt).
+//
+// The diagonal is read directly when a is a
+// *DenseMatrix, *SparseCSR, or *SymBand, and is
+// otherwise found with a.Dim() applications of a.
+// An error is returned if any diagonal entry is not
+// positive.
+func Equilibrate(a LinTran) (scaled LinTran, dInvSqrt linalg.Vector, err error) {
+	var diagonal linalg.Vector
+	if rt, ok := a.(rowTran); ok {
+		diagonal = rowTranDiagonalEntries(rt)
+	} else {
+		diagonal = extractDiagonal(a)
+	}
+	dInvSqrt = make(linalg.Vector, len(diagonal))
+	for i, d := range diagonal {
+		if !(d > 0) {
+			return nil, nil, fmt.Errorf("non-positive diagonal entry %g at index %d", d, i)
+		}
+		dInvSqrt[i] = 1 / math.Sqrt(d)
+	}
+	return equilibratedTran{a: a, scale: dInvSqrt}, dInvSqrt, nil
+}
+
+// ScaleRHS computes D^-1/2*b, the right-hand side of
+// the scaled system; see Equilibrate.
+// It returns a new vector.
+func ScaleRHS(b, dInvSqrt linalg.Vector) linalg.Vector {
+	if len(b) != len(dInvSqrt) {
+		panic("dimension mismatch")
+	}
+	return elementwiseProduct(dInvSqrt, b)
+}
+
+// UnscaleSolution multiplies x component-wise by the
+// dInvSqrt from Equilibrate, turning the solution of
+// the scaled system into the solution of the original
+// one; see Equilibrate.
+// It returns a new vector.
+func UnscaleSolution(x, dInvSqrt linalg.Vector) linalg.Vector {
+	if len(x) != len(dInvSqrt) {
+		panic("dimension mismatch")
+	}
+	return elementwiseProduct(dInvSqrt, x)
+}
+
+type equilibratedTran struct {
+	a     LinTran
+	scale linalg.Vector
+}
+
+func (e equilibratedTran) Dim() int {
+	return e.a.Dim()
+}
+
+func (e equilibratedTran) Apply(v linalg.Vector) linalg.Vector {
+	if len(v) != len(e.scale) {
+		panic("dimension mismatch")
+	}
+	return elementwiseProduct(e.scale, e.a.Apply(elementwiseProduct(e.scale, v)))
+}
@@ -0,0 +1,31 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestEquilibrate(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	dense := &DenseMatrix{Rows: 5, Cols: 5, Data: lt.M.Data}
+	for _, op := range []LinTran{lt, dense} {
+		scaled, dInvSqrt, err := Equilibrate(op)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 5; i++ {
+			basis := make(linalg.Vector, 5)
+			basis[i] = 1
+			if d := scaled.Apply(basis)[i]; d < 1-1e-12 || d > 1+1e-12 {
+				t.Errorf("scaled diagonal entry %d is %f", i, d)
+			}
+		}
+		y := Solve(scaled, ScaleRHS(b, dInvSqrt), 1e-10)
+		checkSolution(t, UnscaleSolution(y, dInvSqrt), realSolution)
+	}
+
+	if _, _, err := Equilibrate(Diagonal{1, 0, 2}); err == nil {
+		t.Error("expected error for zero diagonal entry")
+	}
+}
@@ -0,0 +1,59 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// SolveFlexibleCG is like SolvePreconditioned, but it
+// allows the preconditioner m to change from one
+// iteration to the next, for example because m is
+// itself an inexact iterative solve.
+//
+// It uses the Polak-Ribiere formula for beta, which
+// stays robust when m varies.
+// This requires keeping the previous residual around,
+// so it uses one more vector of storage than standard
+// preconditioned CG.
+func SolveFlexibleCG(t LinTran, m func(r linalg.Vector) linalg.Vector, b linalg.Vector,
+	prec float64) linalg.Vector {
+	return SolveFlexibleCGStoppable(t, m, b, prec, nil)
+}
+
+// SolveFlexibleCGStoppable is like SolveFlexibleCG,
+// but it returns the current solution once cancelChan
+// is closed.
+func SolveFlexibleCGStoppable(t LinTran, m func(r linalg.Vector) linalg.Vector,
+	b linalg.Vector, prec float64, cancelChan <-chan struct{}) linalg.Vector {
+	residual := b.Copy()
+	lastResidual := make(linalg.Vector, len(b))
+	solution := make(linalg.Vector, t.Dim())
+	var conjVec linalg.Vector
+	var lastResidualDot float64
+
+	for iters := 0; residual.NormInf() > prec; iters++ {
+		z := m(residual)
+		residualDot := z.Dot(residual)
+		if iters == 0 {
+			conjVec = z.Copy()
+		} else {
+			beta := (residualDot - z.Dot(lastResidual)) / lastResidualDot
+			conjVec.Scale(beta).Add(z)
+		}
+		lastResidualDot = residualDot
+		if allZero(conjVec) {
+			break
+		}
+
+		appliedConj := t.Apply(conjVec)
+		optimalDistance := residualDot / conjVec.Dot(appliedConj)
+		solution.AddScaled(conjVec, optimalDistance)
+
+		copy(lastResidual, residual)
+		updateResidual(t, b, solution, residual, appliedConj, optimalDistance,
+			iters, residualUpdateFrequency, nil)
+
+		if cancelled(cancelChan) {
+			break
+		}
+	}
+
+	return solution
+}
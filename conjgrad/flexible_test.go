@@ -0,0 +1,25 @@
+package conjgrad
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveFlexibleCG(t *testing.T) {
+	lt, b, realSolution := testProblem()
+
+	// A Jacobi preconditioner which is perturbed
+	// differently on every call.
+	m := func(r linalg.Vector) linalg.Vector {
+		res := make(linalg.Vector, len(r))
+		for i, x := range r {
+			res[i] = x / lt.M.Get(i, i) * (1 + 0.1*rand.Float64())
+		}
+		return res
+	}
+
+	solution := SolveFlexibleCG(lt, m, b, 1e-10)
+	checkSolution(t, solution, realSolution)
+}
@@ -0,0 +1,36 @@
+package conjgrad
+
+import "math"
+
+// NewGivens computes a Givens rotation which zeroes
+// out b in the vector (a, b).
+//
+// The resulting c and s satisfy c^2 + s^2 = 1 and
+// ApplyGivens(c, s, a, b) = (r, 0), where r is the
+// 2-norm of (a, b).
+//
+// The larger of a and b is divided out before
+// squaring, so this does not overflow or underflow
+// for huge or tiny inputs.
+func NewGivens(a, b float64) (c, s float64) {
+	if b == 0 {
+		if a < 0 {
+			return -1, 0
+		}
+		return 1, 0
+	}
+	if math.Abs(b) > math.Abs(a) {
+		t := a / b
+		s = math.Copysign(1/math.Sqrt(1+t*t), b)
+		return t * s, s
+	}
+	t := b / a
+	c = math.Copysign(1/math.Sqrt(1+t*t), a)
+	return c, t * c
+}
+
+// ApplyGivens applies the Givens rotation given by c
+// and s to the vector (x, y).
+func ApplyGivens(c, s, x, y float64) (float64, float64) {
+	return c*x + s*y, -s*x + c*y
+}
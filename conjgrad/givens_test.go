@@ -0,0 +1,35 @@
+package conjgrad
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGivens(t *testing.T) {
+	cases := []struct {
+		a, b float64
+		c, s float64
+		r    float64
+	}{
+		{3, 4, 0.6, 0.8, 5},
+		{-3, 4, -0.6, 0.8, 5},
+		{4, -3, 0.8, -0.6, 5},
+		{0, -2, 0, -1, 2},
+		{-5, 0, -1, 0, 5},
+		{0, 0, 1, 0, 0},
+		{1e300, 1e300, math.Sqrt2 / 2, math.Sqrt2 / 2, math.Sqrt2 * 1e300},
+		{3e-300, 4e-300, 0.6, 0.8, 5e-300},
+	}
+	for _, tc := range cases {
+		c, s := NewGivens(tc.a, tc.b)
+		if math.Abs(c-tc.c) > 1e-12 || math.Abs(s-tc.s) > 1e-12 {
+			t.Errorf("NewGivens(%g, %g): expected (%g, %g) but got (%g, %g)",
+				tc.a, tc.b, tc.c, tc.s, c, s)
+		}
+		r, zero := ApplyGivens(c, s, tc.a, tc.b)
+		scale := math.Max(1e-300, tc.r)
+		if math.Abs(r-tc.r) > 1e-12*scale || math.Abs(zero) > 1e-12*scale {
+			t.Errorf("ApplyGivens(%g, %g): got (%g, %g)", tc.a, tc.b, r, zero)
+		}
+	}
+}
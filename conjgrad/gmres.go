@@ -0,0 +1,127 @@
+package conjgrad
+
+import (
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+const defaultGMRESRestart = 30
+
+// SolveGMRES solves a system of linear equations
+// t*x = b for x using restarted GMRES.
+//
+// Like SolveBiCGSTAB, this works for any invertible
+// t, symmetric or not.
+//
+// The restart argument specifies the size of the
+// Krylov subspace to build before restarting.
+// Larger values use more memory but converge more
+// reliably.
+// If restart is 0 or negative, min(t.Dim(), 30)
+// is used.
+//
+// The prec argument is a bound on the largest
+// absolute value of any component of b-Ax.
+// If a full restart cycle fails to reduce the
+// residual, the current solution is returned.
+func SolveGMRES(t LinTran, b linalg.Vector, prec float64, restart int) linalg.Vector {
+	return SolveGMRESStoppable(t, b, prec, restart, nil)
+}
+
+// SolveGMRESStoppable is like SolveGMRES, but it
+// returns the current solution once cancelChan is
+// closed.
+//
+// Cancellation is checked after every Arnoldi step,
+// not just between restart cycles.
+func SolveGMRESStoppable(t LinTran, b linalg.Vector, prec float64, restart int,
+	cancelChan <-chan struct{}) linalg.Vector {
+	return SolveGMRESDetailed(t, b, prec, restart, cancelChan).Solution
+}
+
+// SolveGMRESDetailed is like SolveGMRESStoppable, but
+// it returns a SolveResult whose Iterations field is
+// the total number of Arnoldi steps.
+func SolveGMRESDetailed(t LinTran, b linalg.Vector, prec float64, restart int,
+	cancelChan <-chan struct{}) SolveResult {
+	if restart <= 0 {
+		restart = defaultGMRESRestart
+		if t.Dim() < restart {
+			restart = t.Dim()
+		}
+	}
+
+	solution := make(linalg.Vector, t.Dim())
+	lastMag := math.Inf(1)
+	var iters int
+	for {
+		residual := b.Copy().Sub(t.Apply(solution))
+		mag := residual.Mag()
+		if residual.MaxAbs() <= prec || !(mag < lastMag) || cancelled(cancelChan) {
+			break
+		}
+		lastMag = mag
+		correction, steps := gmresCycle(t, residual, mag, prec, restart, cancelChan)
+		solution.Add(correction)
+		iters += steps
+	}
+
+	return detailedResult(t, b, solution, iters, prec)
+}
+
+// gmresCycle runs one cycle of GMRES and returns
+// the minimum-residual correction from the Krylov
+// subspace generated by the residual, along with the
+// number of Arnoldi steps taken.
+//
+// If cancelChan is closed, the cycle ends early and
+// the correction from the subspace built so far is
+// returned.
+func gmresCycle(t LinTran, residual linalg.Vector, mag, prec float64,
+	restart int, cancelChan <-chan struct{}) (linalg.Vector, int) {
+	basis := []linalg.Vector{residual.ScaledCopy(1 / mag)}
+	hessenberg := make([]linalg.Vector, 0, restart)
+	cosines := make([]float64, 0, restart)
+	sines := make([]float64, 0, restart)
+	rhs := linalg.Vector{mag}
+
+	for j := 0; j < restart; j++ {
+		next, column := arnoldiStep(t, basis)
+		nextMag := column[j+1]
+
+		for i := 0; i < j; i++ {
+			column[i], column[i+1] = ApplyGivens(cosines[i], sines[i], column[i], column[i+1])
+		}
+		c, s := NewGivens(column[j], column[j+1])
+		column[j], column[j+1] = ApplyGivens(c, s, column[j], column[j+1])
+		cosines = append(cosines, c)
+		sines = append(sines, s)
+		hessenberg = append(hessenberg, column)
+
+		var rhsNext float64
+		rhs[j], rhsNext = ApplyGivens(c, s, rhs[j], 0)
+		rhs = append(rhs, rhsNext)
+
+		if nextMag == 0 || math.Abs(rhsNext) <= prec || cancelled(cancelChan) {
+			break
+		}
+		basis = append(basis, next.Scale(1/nextMag))
+	}
+
+	// Solve the upper-triangular system Hy = g.
+	coeffs := make(linalg.Vector, len(hessenberg))
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		sum := rhs[i]
+		for k := i + 1; k < len(coeffs); k++ {
+			sum -= hessenberg[k][i] * coeffs[k]
+		}
+		coeffs[i] = sum / hessenberg[i][i]
+	}
+
+	correction := make(linalg.Vector, len(residual))
+	for i, x := range coeffs {
+		correction.Add(basis[i].Copy().Scale(x))
+	}
+	return correction, len(coeffs)
+}
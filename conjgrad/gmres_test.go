@@ -0,0 +1,11 @@
+package conjgrad
+
+import "testing"
+
+func TestSolveGMRES(t *testing.T) {
+	lt, b, realSolution := nonSymmetricProblem()
+	for _, restart := range []int{0, 2, 4} {
+		solution := SolveGMRES(lt, b, 1e-10, restart)
+		checkSolution(t, solution, realSolution)
+	}
+}
@@ -0,0 +1,19 @@
+// Package gonumadapt converts between the vector and
+// operator types used by conjgrad and the ones used by
+// gonum.org/v1/gonum/mat.
+//
+// This is the only package in the repository that
+// depends on gonum, which is not vendored.
+// Everything but this comment is behind the gonum
+// build tag, so a plain go build, go vet or go test of
+// ./... compiles an empty package here and never checks
+// the adapter.
+// To build and test it, fetch gonum and pass the tag:
+//
+//	go get gonum.org/v1/gonum/mat
+//	go vet -tags gonum ./conjgrad/gonumadapt
+//	go test -tags gonum ./conjgrad/gonumadapt
+//
+// All conversions copy their data, so modifying the
+// result never affects the input.
+package gonumadapt
@@ -0,0 +1,73 @@
+//go:build gonum
+
+package gonumadapt
+
+import (
+	"github.com/unixpickle/num-analysis/conjgrad"
+	"github.com/unixpickle/num-analysis/linalg"
+	"gonum.org/v1/gonum/mat"
+)
+
+// FromGonumVec copies a gonum vector into a new
+// linalg.Vector.
+func FromGonumVec(v mat.Vector) linalg.Vector {
+	res := make(linalg.Vector, v.Len())
+	for i := range res {
+		res[i] = v.AtVec(i)
+	}
+	return res
+}
+
+// ToGonumVec copies a linalg.Vector into a new
+// gonum vector.
+//
+// Since gonum does not allow zero-length vectors to
+// be created with mat.NewVecDense, an empty v yields
+// an empty *mat.VecDense.
+func ToGonumVec(v linalg.Vector) *mat.VecDense {
+	if len(v) == 0 {
+		return &mat.VecDense{}
+	}
+	return mat.NewVecDense(len(v), v.Copy())
+}
+
+// FromGonumMatrix creates a conjgrad.LinTran which
+// multiplies vectors by a square gonum matrix.
+//
+// The matrix is copied, so later changes to m will
+// not affect the LinTran.
+func FromGonumMatrix(m mat.Matrix) conjgrad.LinTran {
+	rows, cols := m.Dims()
+	if rows != cols {
+		panic("matrix must be square")
+	}
+	if rows == 0 {
+		return gonumTran{}
+	}
+	return gonumTran{mat.DenseCopyOf(m)}
+}
+
+type gonumTran struct {
+	m *mat.Dense
+}
+
+func (g gonumTran) Dim() int {
+	if g.m == nil {
+		return 0
+	}
+	rows, _ := g.m.Dims()
+	return rows
+}
+
+func (g gonumTran) Apply(v linalg.Vector) linalg.Vector {
+	if len(v) != g.Dim() {
+		panic("dimension mismatch")
+	}
+	if len(v) == 0 {
+		return linalg.Vector{}
+	}
+	in := mat.NewVecDense(len(v), v)
+	res := make(linalg.Vector, len(v))
+	mat.NewVecDense(len(res), res).MulVec(g.m, in)
+	return res
+}
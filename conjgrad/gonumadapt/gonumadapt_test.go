@@ -0,0 +1,64 @@
+//go:build gonum
+
+package gonumadapt
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestVecRoundTrip(t *testing.T) {
+	v := linalg.Vector{1, -2, 3.5}
+	g := ToGonumVec(v)
+	g.SetVec(0, 7)
+	if v[0] != 1 {
+		t.Error("ToGonumVec did not copy its input")
+	}
+	back := FromGonumVec(g)
+	expected := linalg.Vector{7, -2, 3.5}
+	for i, x := range expected {
+		if back[i] != x {
+			t.Fatalf("expected %v but got %v", expected, back)
+		}
+	}
+	back[1] = 5
+	if g.AtVec(1) != -2 {
+		t.Error("FromGonumVec did not copy its input")
+	}
+
+	empty := ToGonumVec(linalg.Vector{})
+	if empty.Len() != 0 || len(FromGonumVec(empty)) != 0 {
+		t.Error("unexpected empty vector length", empty.Len())
+	}
+}
+
+func TestFromGonumMatrix(t *testing.T) {
+	m := mat.NewDense(2, 2, []float64{1, 2, 3, 4})
+	lt := FromGonumMatrix(m)
+	m.Set(0, 0, 100)
+	if lt.Dim() != 2 {
+		t.Fatal("unexpected dimension", lt.Dim())
+	}
+	in := linalg.Vector{1, -1}
+	res := lt.Apply(in)
+	if res[0] != -1 || res[1] != -1 {
+		t.Error("unexpected product", res)
+	}
+	if in[0] != 1 || in[1] != -1 {
+		t.Error("Apply modified its input")
+	}
+
+	empty := FromGonumMatrix(&mat.Dense{})
+	if empty.Dim() != 0 || len(empty.Apply(linalg.Vector{})) != 0 {
+		t.Error("unexpected result for empty matrix")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for non-square matrix")
+		}
+	}()
+	FromGonumMatrix(mat.NewDense(2, 3, nil))
+}
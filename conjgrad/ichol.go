@@ -0,0 +1,105 @@
+package conjgrad
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// ICPreconditioner is a Preconditioner which uses an
+// incomplete Cholesky factorization L*L' of a sparse
+// matrix as M.
+type ICPreconditioner struct {
+	// lower stores L, whose rows have their diagonal
+	// entry last.
+	lower *SparseCSR
+}
+
+// NewIncompleteCholesky computes the zero-fill
+// incomplete Cholesky factorization IC(0) of a
+// symmetric positive-definite sparse matrix.
+//
+// The factor L has the same sparsity pattern as the
+// lower triangle of a.
+// Only the lower triangle of a is accessed.
+//
+// An error is returned if the factorization breaks
+// down because of a zero or negative pivot, which can
+// happen for some SPD matrices as well as for
+// matrices which are not SPD.
+func NewIncompleteCholesky(a *SparseCSR) (*ICPreconditioner, error) {
+	lower := &SparseCSR{dim: a.dim, rowPtr: make([]int, a.dim+1)}
+	for row := 0; row < a.dim; row++ {
+		rowStart := len(lower.values)
+		var diagonal float64
+		for idx := a.rowPtr[row]; idx < a.rowPtr[row+1]; idx++ {
+			col := a.colIndices[idx]
+			if col > row {
+				break
+			}
+			if col == row {
+				diagonal = a.values[idx]
+				for _, x := range lower.values[rowStart:] {
+					diagonal -= x * x
+				}
+				break
+			}
+			sum := a.values[idx] - lower.rowDot(rowStart, len(lower.values), col)
+			lower.values = append(lower.values, sum/lower.values[lower.rowPtr[col+1]-1])
+			lower.colIndices = append(lower.colIndices, col)
+		}
+		if !(diagonal > 0) {
+			return nil, fmt.Errorf("non-positive pivot %g at row %d", diagonal, row)
+		}
+		lower.values = append(lower.values, math.Sqrt(diagonal))
+		lower.colIndices = append(lower.colIndices, row)
+		lower.rowPtr[row+1] = len(lower.values)
+	}
+	return &ICPreconditioner{lower: lower}, nil
+}
+
+// rowDot computes the dot product between the partial
+// row stored in values[start:end] and the row of L
+// for the given column, excluding its diagonal.
+func (s *SparseCSR) rowDot(start, end, col int) float64 {
+	var sum float64
+	other, otherEnd := s.rowPtr[col], s.rowPtr[col+1]-1
+	for start < end && other < otherEnd {
+		c1, c2 := s.colIndices[start], s.colIndices[other]
+		if c1 == c2 {
+			sum += s.values[start] * s.values[other]
+			start++
+			other++
+		} else if c1 < c2 {
+			start++
+		} else {
+			other++
+		}
+	}
+	return sum
+}
+
+// ApplyInverse solves (L*L')x = r for x.
+func (i *ICPreconditioner) ApplyInverse(r linalg.Vector) linalg.Vector {
+	l := i.lower
+	if len(r) != l.dim {
+		panic("dimension mismatch")
+	}
+	res := r.Copy()
+	for row := 0; row < l.dim; row++ {
+		diagIdx := l.rowPtr[row+1] - 1
+		for idx := l.rowPtr[row]; idx < diagIdx; idx++ {
+			res[row] -= l.values[idx] * res[l.colIndices[idx]]
+		}
+		res[row] /= l.values[diagIdx]
+	}
+	for row := l.dim - 1; row >= 0; row-- {
+		diagIdx := l.rowPtr[row+1] - 1
+		res[row] /= l.values[diagIdx]
+		for idx := l.rowPtr[row]; idx < diagIdx; idx++ {
+			res[l.colIndices[idx]] -= l.values[idx] * res[row]
+		}
+	}
+	return res
+}
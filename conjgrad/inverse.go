@@ -0,0 +1,30 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// Inverse computes the explicit inverse of a
+// symmetric positive-definite t by solving t*x = e_j
+// for every standard basis vector e_j.
+//
+// The columns are solved together with SolveBlock, so
+// they share one Krylov space.
+// Nevertheless, this amounts to t.Dim() solves and
+// O(n^2) memory, so it is only practical for small to
+// medium operators.
+func Inverse(t LinTran, prec float64) *DenseMatrix {
+	dim := t.Dim()
+	basis := make([]linalg.Vector, dim)
+	for i := range basis {
+		basis[i] = make(linalg.Vector, dim)
+		basis[i][i] = 1
+	}
+	columns := SolveBlock(t, basis, prec)
+
+	res := NewDenseMatrix(dim, dim)
+	for j, column := range columns {
+		for i, x := range column {
+			res.Set(i, j, x)
+		}
+	}
+	return res
+}
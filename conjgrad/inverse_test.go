@@ -0,0 +1,17 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestInverse(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	inv := Inverse(lt, 1e-12)
+	checkSolution(t, inv.Apply(b), realSolution)
+
+	product := inv.Matrix().Mul(lt.M)
+	checkSolution(t, linalg.Vector(product.Data),
+		linalg.Vector(linalg.NewMatrixIdentity(5).Data))
+}
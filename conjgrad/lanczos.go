@@ -0,0 +1,86 @@
+package conjgrad
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// lanczosBreakdown is the size of an off-diagonal
+// entry, relative to the largest entry seen so far,
+// below which the Lanczos process is considered to
+// have found an invariant subspace.
+const lanczosBreakdown = 1e-12
+
+// Lanczos runs the Lanczos process on a symmetric
+// operator t for the given number of steps, starting
+// from the vector start.
+//
+// It returns the diagonal (alphas) and off-diagonal
+// (betas) entries of the symmetric tridiagonal matrix
+// T = Q'*t*Q, where the columns of Q are an orthonormal
+// basis for the Krylov subspace generated by start.
+// There is one fewer beta than there are alphas.
+//
+// The starting vector is normalized automatically.
+// If an invariant subspace is found before the last
+// step, fewer than steps alphas are returned.
+func Lanczos(t LinTran, start linalg.Vector, steps int) (alphas, betas linalg.Vector) {
+	checkStart(t, start)
+	vec := start.Unit()
+
+	var lastVec linalg.Vector
+	var beta, scale float64
+	for i := 0; i < steps; i++ {
+		next := t.Apply(vec)
+		if i > 0 {
+			next.AddScaled(lastVec, -beta)
+		}
+		alpha := next.Dot(vec)
+		next.AddScaled(vec, -alpha)
+		alphas = append(alphas, alpha)
+		scale = math.Max(scale, math.Abs(alpha))
+
+		if i == steps-1 {
+			break
+		}
+		beta = next.Mag()
+		if beta <= lanczosBreakdown*math.Max(scale, beta) || beta == 0 {
+			break
+		}
+		scale = math.Max(scale, beta)
+		betas = append(betas, beta)
+		lastVec, vec = vec, next.Scale(1/beta)
+	}
+	return
+}
+
+// EstimateEigenvalueBounds approximates the smallest
+// and largest eigenvalues of a symmetric operator t.
+//
+// It runs iters steps of Lanczos from a random start
+// and returns the extreme eigenvalues of the resulting
+// tridiagonal matrix (the Ritz values).
+// These are only approximations: for any number of
+// iterations, lambdaMin is at least the true smallest
+// eigenvalue and lambdaMax is at most the true largest
+// eigenvalue, and both converge to the true values as
+// iters grows.
+//
+// The start vector is drawn from gen, so the result is
+// reproducible for a seeded gen.
+// This panics if iters is not positive or t has no
+// dimensions.
+func EstimateEigenvalueBounds(t LinTran, iters int, gen *rand.Rand) (lambdaMin,
+	lambdaMax float64) {
+	if iters <= 0 {
+		panic("number of iterations must be positive")
+	}
+	if t.Dim() == 0 {
+		panic("operator has no dimensions")
+	}
+	alphas, betas := Lanczos(t, RandomVector(t.Dim(), gen), iters)
+	ritz := tridiagEigenvalues(alphas, betas)
+	return ritz[0], ritz[len(ritz)-1]
+}
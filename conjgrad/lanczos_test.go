@@ -0,0 +1,89 @@
+package conjgrad
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestLanczos(t *testing.T) {
+	lt, b, _ := testProblem()
+	alphas, betas := Lanczos(lt, b, 3)
+	if len(alphas) != 3 || len(betas) != 2 {
+		t.Fatal("unexpected sizes:", len(alphas), len(betas))
+	}
+	q := b.Copy().Scale(1 / b.Mag())
+	if expected := q.Dot(lt.Apply(q)); math.Abs(expected-alphas[0]) > 1e-10 {
+		t.Error("expected first alpha", expected, "but got", alphas[0])
+	}
+
+	// The trace of T is the trace of the operator once
+	// the full space has been spanned.
+	alphas, betas = Lanczos(lt, b, 5)
+	var trace, expectedTrace float64
+	for i, a := range alphas {
+		trace += a
+		expectedTrace += lt.M.Get(i, i)
+	}
+	if math.Abs(trace-expectedTrace) > 1e-8 {
+		t.Error("expected trace", expectedTrace, "but got", trace)
+	}
+
+	// An eigenvector spans an invariant subspace.
+	alphas, betas = Lanczos(Diagonal{1, 2, 3}, linalg.Vector{0, 2, 0}, 3)
+	if len(alphas) != 1 || len(betas) != 0 || alphas[0] != 2 {
+		t.Error("unexpected result for invariant subspace:", alphas, betas)
+	}
+}
+
+func TestEstimateEigenvalueBounds(t *testing.T) {
+	diag := make(Diagonal, 50)
+	for i := range diag {
+		diag[i] = float64(i + 1)
+	}
+	gen := rand.New(rand.NewSource(1))
+	lambdaMin, lambdaMax := EstimateEigenvalueBounds(diag, 50, gen)
+	if math.Abs(lambdaMin-1) > 1e-6 || math.Abs(lambdaMax-50) > 1e-6 {
+		t.Error("expected bounds (1, 50) but got", lambdaMin, lambdaMax)
+	}
+	lambdaMin, lambdaMax = EstimateEigenvalueBounds(diag, 10, gen)
+	if lambdaMin < 1-1e-8 || lambdaMax > 50+1e-8 || lambdaMax < 40 {
+		t.Error("unexpected bounds", lambdaMin, lambdaMax)
+	}
+
+	min1, max1 := EstimateEigenvalueBounds(diag, 5, rand.New(rand.NewSource(2)))
+	min2, max2 := EstimateEigenvalueBounds(diag, 5, rand.New(rand.NewSource(2)))
+	if min1 != min2 || max1 != max2 {
+		t.Error("results differ for the same seed")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for zero iterations")
+		}
+	}()
+	EstimateEigenvalueBounds(diag, 0, gen)
+}
+
+func TestTridiagEigenvalues(t *testing.T) {
+	// The second difference matrix has eigenvalues
+	// 2 - 2*cos(k*pi/(n+1)).
+	n := 6
+	alphas := make(linalg.Vector, n)
+	betas := make(linalg.Vector, n-1)
+	for i := range alphas {
+		alphas[i] = 2
+	}
+	for i := range betas {
+		betas[i] = -1
+	}
+	vals := tridiagEigenvalues(alphas, betas)
+	for k, val := range vals {
+		expected := 2 - 2*math.Cos(float64(k+1)*math.Pi/float64(n+1))
+		if math.Abs(val-expected) > 1e-10 {
+			t.Errorf("eigenvalue %d should be %f but got %f", k, expected, val)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package conjgrad
+
+import (
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+const (
+	defaultArmijoConstant = 1e-4
+	defaultMaxLineSteps   = 60
+	backtrackFactor       = 0.5
+)
+
+// LineSearchOptions configures LineSearchWith.
+// Zero fields take on default values.
+type LineSearchOptions struct {
+	// Armijo is the fraction of the predicted decrease
+	// which an accepted step must achieve.
+	// The default is 1e-4.
+	Armijo float64
+
+	// Wolfe, if positive, also requires the magnitude
+	// of the directional derivative at an accepted step
+	// to be at most Wolfe times its magnitude at x.
+	// This is the strong Wolfe curvature condition.
+	Wolfe float64
+
+	// InitialStep is the first step length to try.
+	// The default is 1.
+	InitialStep float64
+
+	// MaxSteps bounds the number of trial steps.
+	// The default is 60.
+	MaxSteps int
+}
+
+// LineSearch finds a step length along dir from x
+// which satisfies the Armijo condition, using
+// backtracking from a step of 1.
+//
+// If no step is acceptable, the smallest trial step
+// is returned.
+func LineSearch(f func(linalg.Vector) float64, grad func(linalg.Vector) linalg.Vector,
+	x, dir linalg.Vector) float64 {
+	return LineSearchWith(f, grad, x, dir, &LineSearchOptions{})
+}
+
+// LineSearchWith is like LineSearch, but with the
+// given options.
+//
+// If opts.Wolfe is set, the step may grow as well as
+// shrink, since the curvature condition rules out
+// steps which are too short.
+// In that case, the longest step which achieved
+// sufficient decrease is returned on failure, or the
+// smallest trial step if there was none.
+func LineSearchWith(f func(linalg.Vector) float64, grad func(linalg.Vector) linalg.Vector,
+	x, dir linalg.Vector, opts *LineSearchOptions) float64 {
+	armijo := opts.Armijo
+	if armijo == 0 {
+		armijo = defaultArmijoConstant
+	}
+	step := opts.InitialStep
+	if step == 0 {
+		step = 1
+	}
+	maxSteps := opts.MaxSteps
+	if maxSteps == 0 {
+		maxSteps = defaultMaxLineSteps
+	}
+
+	value := f(x)
+	slope := grad(x).Dot(dir)
+	smallest := step
+	lower, upper := 0.0, math.Inf(1)
+	for i := 0; i < maxSteps; i++ {
+		smallest = math.Min(smallest, step)
+		point := x.Copy().AddScaled(dir, step)
+		// A strict decrease keeps a step which is lost
+		// to rounding from being accepted.
+		if f(point) >= value+armijo*step*slope {
+			upper = step
+		} else if opts.Wolfe == 0 {
+			return step
+		} else {
+			newSlope := grad(point).Dot(dir)
+			if math.Abs(newSlope) <= -opts.Wolfe*slope {
+				return step
+			} else if newSlope > 0 {
+				upper = step
+			} else {
+				lower = step
+			}
+		}
+		if math.IsInf(upper, 1) {
+			step /= backtrackFactor
+		} else if lower == 0 {
+			step *= backtrackFactor
+		} else {
+			step = (lower + upper) / 2
+		}
+	}
+	if lower != 0 {
+		return lower
+	}
+	return smallest
+}
@@ -0,0 +1,40 @@
+package conjgrad
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestLineSearch(t *testing.T) {
+	f := func(x linalg.Vector) float64 {
+		return x.Dot(x)
+	}
+	grad := func(x linalg.Vector) linalg.Vector {
+		return x.ScaledCopy(2)
+	}
+	x := linalg.Vector{1, -2}
+	dir := grad(x).Scale(-1)
+
+	if step := LineSearch(f, grad, x, dir); step != 0.5 {
+		t.Error("expected step 0.5 but got", step)
+	}
+
+	// A short initial step must grow to satisfy the
+	// curvature condition.
+	step := LineSearchWith(f, grad, x, dir, &LineSearchOptions{
+		Wolfe:       0.1,
+		InitialStep: 1e-3,
+	})
+	newSlope := grad(x.Copy().AddScaled(dir, step)).Dot(dir)
+	if math.Abs(newSlope) > 0.1*math.Abs(grad(x).Dot(dir)) {
+		t.Error("step", step, "violates curvature condition")
+	}
+
+	// An uphill direction cannot be satisfied.
+	step = LineSearchWith(f, grad, x, dir.Scale(-1), &LineSearchOptions{MaxSteps: 10})
+	if expected := math.Pow(backtrackFactor, 9); step != expected {
+		t.Error("expected step", expected, "but got", step)
+	}
+}
@@ -1,6 +1,10 @@
 package conjgrad
 
-import "github.com/unixpickle/num-analysis/linalg"
+import (
+	"errors"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
 
 // A LinTran is a square linear transformation.
 type LinTran interface {
@@ -14,6 +18,78 @@ type LinTran interface {
 	Apply(v linalg.Vector) linalg.Vector
 }
 
+// A LinTranInPlace is a LinTran which can write its
+// output into an existing vector.
+//
+// The solvers check for this interface and use it to
+// avoid allocating a new vector on every iteration.
+type LinTranInPlace interface {
+	LinTran
+
+	// ApplyInto applies this linear transformation to
+	// src and stores the result in dst.
+	// The vectors dst and src will not overlap.
+	ApplyInto(dst, src linalg.Vector)
+}
+
+// A TransposableLinTran is a LinTran which can also
+// apply its transpose, as needed by solvers for
+// non-symmetric systems.
+//
+// Both *DenseMatrix and *SparseCSR are
+// TransposableLinTrans.
+type TransposableLinTran interface {
+	LinTran
+
+	// ApplyTranspose applies the transpose of this
+	// linear transformation to a vector.
+	ApplyTranspose(v linalg.Vector) linalg.Vector
+}
+
+// ErrNotTransposable is returned by solvers which
+// need a TransposableLinTran but were given an
+// operator that cannot apply its transpose.
+var ErrNotTransposable = errors.New("operator does not implement ApplyTranspose")
+
+// squareRect adapts a TransposableLinTran to the
+// LinTranRect interface.
+type squareRect struct {
+	TransposableLinTran
+}
+
+func (s squareRect) Rows() int {
+	return s.Dim()
+}
+
+func (s squareRect) Cols() int {
+	return s.Dim()
+}
+
+// applyInto applies t to src, writing the result to
+// dst if t is a LinTranInPlace.
+// It returns the vector holding the result, which is
+// only guaranteed to be dst in the in-place case.
+func applyInto(t LinTran, dst, src linalg.Vector) linalg.Vector {
+	if inPlace, ok := t.(LinTranInPlace); ok {
+		inPlace.ApplyInto(dst, src)
+		return dst
+	}
+	return t.Apply(src)
+}
+
+// A rowTran is a LinTran which exposes the entries
+// of its matrix, allowing for operations that need
+// more than matrix-vector products.
+//
+// *DenseMatrix, *SparseCSR, and *SymBand are rowTrans.
+type rowTran interface {
+	LinTran
+
+	// iterRow calls f for every non-zero entry in the
+	// given row, in order of increasing column.
+	iterRow(row int, f func(col int, val float64))
+}
+
 // MatLinTran is a LinTran which is defined as
 // L such that L(v) = M*v for a matrix M.
 //
@@ -35,12 +111,65 @@ func (m MatLinTran) Apply(v linalg.Vector) linalg.Vector {
 	return linalg.Vector(res.Data)
 }
 
-type identity struct{}
+// Rows returns the number of rows in the matrix.
+func (m MatLinTran) Rows() int {
+	return m.M.Rows
+}
+
+// Cols returns the number of columns in the matrix.
+func (m MatLinTran) Cols() int {
+	return m.M.Cols
+}
+
+// ApplyTranspose returns M'*v.
+func (m MatLinTran) ApplyTranspose(v linalg.Vector) linalg.Vector {
+	row := &linalg.Matrix{Rows: 1, Cols: len(v), Data: v}
+	res := row.Mul(m.M)
+	return linalg.Vector(res.Data)
+}
+
+// A LinTranRect is a linear transformation from
+// one space to another, possibly of a different
+// dimension.
+type LinTranRect interface {
+	// Rows returns the dimension of the output.
+	Rows() int
+
+	// Cols returns the dimension of the input.
+	Cols() int
+
+	// Apply applies the linear transformation
+	// to a vector of size Cols() and returns a
+	// vector of size Rows().
+	Apply(v linalg.Vector) linalg.Vector
+
+	// ApplyTranspose applies the transpose of the
+	// linear transformation to a vector of size
+	// Rows() and returns a vector of size Cols().
+	ApplyTranspose(v linalg.Vector) linalg.Vector
+}
+
+// NewFuncTran creates a LinTran of the given dimension
+// whose Apply method calls the apply function.
+//
+// The apply function must be linear for the result to
+// be meaningful.
+func NewFuncTran(dim int, apply func(linalg.Vector) linalg.Vector) LinTran {
+	if dim <= 0 {
+		panic("dimension must be positive")
+	}
+	return &funcTran{dim: dim, apply: apply}
+}
+
+type funcTran struct {
+	dim   int
+	apply func(linalg.Vector) linalg.Vector
+}
 
-func (_ identity) Dim() int {
-	return 0
+func (f *funcTran) Dim() int {
+	return f.dim
 }
 
-func (_ identity) Apply(v linalg.Vector) linalg.Vector {
-	return v
+func (f *funcTran) Apply(v linalg.Vector) linalg.Vector {
+	return f.apply(v)
 }
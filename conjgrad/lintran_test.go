@@ -0,0 +1,200 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestDiagonal(t *testing.T) {
+	d := Diagonal{2, -4, 0.5}
+	checkSolution(t, d.Apply(linalg.Vector{1, 2, 3}), linalg.Vector{2, -8, 1.5})
+	checkSolution(t, d.Inverse().Apply(linalg.Vector{1, 2, 3}), linalg.Vector{0.5, -0.5, 6})
+
+	lt, b, realSolution := testProblem()
+	diag := make(Diagonal, lt.Dim())
+	for i := range diag {
+		diag[i] = lt.M.Get(i, i)
+	}
+	checkSolution(t, SolvePrec(lt, diag.Inverse(), b, 1e-8), realSolution)
+}
+
+func TestSumTran(t *testing.T) {
+	sum := SumTran(Diagonal{1, 2}, Diagonal{3, 4}, Diagonal{-1, 1})
+	if sum.Dim() != 2 {
+		t.Error("unexpected dimension", sum.Dim())
+	}
+	checkSolution(t, sum.Apply(linalg.Vector{1, -1}), linalg.Vector{3, -7})
+
+	identity := NewFuncTran(2, func(v linalg.Vector) linalg.Vector { return v })
+	v := linalg.Vector{1, 2}
+	checkSolution(t, SumTran(identity, IdentityTran(2)).Apply(v), linalg.Vector{2, 4})
+	checkSolution(t, v, linalg.Vector{1, 2})
+}
+
+func TestProductTran(t *testing.T) {
+	lt, _, _ := testProblem()
+	product := ProductTran(Diagonal{1, 2, 3, 4, 5}, lt)
+	v := linalg.Vector{1, -1, 2, -2, 3}
+	expected := Diagonal{1, 2, 3, 4, 5}.Apply(lt.Apply(v))
+	checkSolution(t, product.Apply(v), expected)
+}
+
+func TestIdentityTran(t *testing.T) {
+	v := linalg.Vector{1, 2, 3}
+	res := IdentityTran(3).Apply(v)
+	res[0] = 5
+	if v[0] != 1 {
+		t.Error("identity should not alias its input")
+	}
+}
+
+func TestScaledTran(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	regularized := SumTran(lt, ScaledTran(2, IdentityTran(lt.Dim())))
+	v := linalg.Vector{1, -1, 2, -2, 3}
+	expected := lt.Apply(v).Add(v.Copy().Scale(2))
+	checkSolution(t, regularized.Apply(v), expected)
+	if v[0] != 1 || v[4] != 3 {
+		t.Error("input vector was modified")
+	}
+
+	scaled := SolvePrec(ScaledTran(0.5, lt), nil, b, 1e-8)
+	checkSolution(t, scaled.Scale(0.5), realSolution)
+}
+
+func TestKroneckerTran(t *testing.T) {
+	a := &DenseMatrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 3, 4}}
+	b := &DenseMatrix{Rows: 3, Cols: 3, Data: []float64{2, -1, 0, -1, 2, -1, 0, -1, 2}}
+	kron := KroneckerTran(a, b)
+	if kron.Dim() != 6 {
+		t.Fatalf("expected dimension 6 but got %d", kron.Dim())
+	}
+
+	dense := NewDenseMatrix(6, 6)
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			for k := 0; k < 3; k++ {
+				for l := 0; l < 3; l++ {
+					dense.Set(i*3+k, j*3+l, a.At(i, j)*b.At(k, l))
+				}
+			}
+		}
+	}
+	v := linalg.Vector{1, -2, 3, 0.5, 4, -1}
+	checkSolution(t, kron.Apply(v), dense.Apply(v))
+}
+
+func TestBlockDiagonalTran(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	diag := Diagonal{2, 4}
+	block := BlockDiagonalTran(lt, diag, lt)
+	if block.Dim() != 12 {
+		t.Fatalf("expected dimension 12 but got %d", block.Dim())
+	}
+
+	var bigB, expected linalg.Vector
+	bigB = append(append(append(bigB, b...), 2, 8), b...)
+	expected = append(append(append(expected, realSolution...), 1, 2), realSolution...)
+	checkSolution(t, SolvePrec(block, nil, bigB, 1e-10), expected)
+}
+
+func TestPermutationTran(t *testing.T) {
+	perm := NewPermutationTran([]int{2, 0, 3, 1})
+	v := linalg.Vector{10, 20, 30, 40}
+	checkSolution(t, perm.Apply(v), linalg.Vector{30, 10, 40, 20})
+	checkSolution(t, perm.Inverse().Apply(perm.Apply(v)), v)
+
+	// Solve the symmetrically permuted system P*A*P'.
+	lt, b, realSolution := testProblem()
+	p := NewPermutationTran([]int{4, 2, 0, 1, 3})
+	permuted := ProductTran(p, ProductTran(lt, p.Inverse()))
+	solution := SolvePrec(permuted, nil, p.Apply(b), 1e-10)
+	checkSolution(t, p.Inverse().Apply(solution), realSolution)
+
+	for _, bad := range [][]int{{0, 0}, {0, 2}, {-1, 0}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for %v", bad)
+				}
+			}()
+			NewPermutationTran(bad)
+		}()
+	}
+}
+
+func TestFuncTran(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	ft := NewFuncTran(lt.Dim(), lt.Apply)
+	if ft.Dim() != 5 {
+		t.Error("unexpected dimension", ft.Dim())
+	}
+	checkSolution(t, SolvePrec(ft, nil, b, 1e-8), realSolution)
+}
+
+type inPlaceTran struct {
+	DenseMatrix
+	applyCalls int
+	intoCalls  int
+}
+
+func (i *inPlaceTran) Apply(v linalg.Vector) linalg.Vector {
+	i.applyCalls++
+	return i.DenseMatrix.Apply(v)
+}
+
+func (i *inPlaceTran) ApplyInto(dst, src linalg.Vector) {
+	i.intoCalls++
+	i.DenseMatrix.ApplyInto(dst, src)
+}
+
+func TestLinTranInPlace(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	tran := &inPlaceTran{DenseMatrix: DenseMatrix{Rows: 5, Cols: 5, Data: lt.M.Data}}
+	var solver Solver
+	checkSolution(t, solver.Solve(tran, b, 1e-10), realSolution)
+	if tran.intoCalls == 0 {
+		t.Error("ApplyInto was never called")
+	}
+	if tran.applyCalls != 0 {
+		t.Errorf("expected no Apply calls but got %d", tran.applyCalls)
+	}
+}
+
+func TestOuter(t *testing.T) {
+	u := linalg.Vector{1, 2, 3}
+	v := linalg.Vector{4, -1, 0.5}
+	dense := Outer(u, v)
+	if dense.Rows != 3 || dense.Cols != 3 || dense.At(2, 1) != -3 || dense.At(1, 2) != 1 {
+		t.Error("unexpected outer product", dense.Data)
+	}
+	rect := Outer(u, linalg.Vector{1, 2})
+	if rect.Rows != 3 || rect.Cols != 2 || rect.At(2, 1) != 6 {
+		t.Error("unexpected outer product", rect.Data)
+	}
+
+	x := linalg.Vector{0.3, -2, 7}
+	checkSolution(t, OuterTran(u, v).Apply(x), dense.Apply(x))
+}
+
+func TestSaddlePointTran(t *testing.T) {
+	a := Diagonal{2, 3, 4}
+	b := MatLinTran{M: &linalg.Matrix{Rows: 1, Cols: 3, Data: []float64{1, 1, 1}}}
+	kkt := SaddlePointTran(a, b)
+	if kkt.Dim() != 4 {
+		t.Fatal("unexpected dimension", kkt.Dim())
+	}
+	checkSolution(t, kkt.Apply(linalg.Vector{1, 2, 3, -1}), linalg.Vector{1, 5, 11, 6})
+
+	expected := linalg.Vector{0.5, -1, 2, 3}
+	rhs := kkt.Apply(expected)
+	checkSolution(t, SolveMINRES(kkt, rhs, 1e-10, nil), expected)
+
+	// An operator which returns its argument must not
+	// corrupt the input or the constraint product.
+	identity := NewFuncTran(3, func(v linalg.Vector) linalg.Vector { return v })
+	v := linalg.Vector{1, 2, 3, -1}
+	checkSolution(t, SaddlePointTran(identity, b).Apply(v), linalg.Vector{0, 1, 2, 6})
+	checkSolution(t, v, linalg.Vector{1, 2, 3, -1})
+}
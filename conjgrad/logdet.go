@@ -0,0 +1,56 @@
+package conjgrad
+
+import (
+	"math"
+	"math/rand"
+)
+
+// defaultLogDetSteps is the maximum number of Lanczos
+// steps per probe used by LogDet.
+const defaultLogDetSteps = 30
+
+// LogDet estimates the natural logarithm of the
+// determinant of a symmetric positive-definite t
+// using stochastic Lanczos quadrature.
+//
+// It is like LogDetSLQ with min(t.Dim(), 30) Lanczos
+// steps and the global random source.
+func LogDet(t LinTran, probes int) float64 {
+	steps := defaultLogDetSteps
+	if t.Dim() < steps {
+		steps = t.Dim()
+	}
+	return LogDetSLQ(t, probes, steps, nil)
+}
+
+// LogDetSLQ estimates log(det(t)) for a symmetric
+// positive-definite t using only t.Apply.
+//
+// For each of the probes random sign vectors z, it
+// runs steps iterations of Lanczos from z and uses
+// Gauss quadrature on the resulting tridiagonal matrix
+// to approximate z'*log(t)*z.
+// The average of these is a Hutchinson estimate of
+// the trace of log(t), which is the log-determinant.
+//
+// The result is a stochastic estimate, whose variance
+// decreases like 1/probes.
+// More steps reduce the quadrature error.
+// If gen is nil, the global random source is used.
+func LogDetSLQ(t LinTran, probes, steps int, gen *rand.Rand) float64 {
+	if probes <= 0 || steps <= 0 {
+		panic("probes and steps must be positive")
+	}
+	dim := t.Dim()
+	var sum float64
+	for i := 0; i < probes; i++ {
+		probe := rademacherVector(gen, dim)
+		alphas, betas := Lanczos(t, probe, steps)
+		eigs := tridiagEigenvalues(alphas, betas)
+		weights := tridiagFirstWeights(alphas, betas, eigs)
+		for k, eig := range eigs {
+			sum += weights[k] * math.Log(eig)
+		}
+	}
+	return sum * float64(dim) / float64(probes)
+}
@@ -0,0 +1,52 @@
+package conjgrad
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestLogDetDiagonal(t *testing.T) {
+	// With sign probes, the estimate is exact for a
+	// diagonal matrix.
+	diag := Diagonal(linalg.Vector{1, 2, 3, 4, 5, 6})
+	expected := math.Log(720)
+	if actual := LogDet(diag, 3); math.Abs(actual-expected) > 1e-8 {
+		t.Errorf("expected %f but got %f", expected, actual)
+	}
+}
+
+func TestLogDetSLQ(t *testing.T) {
+	const dim = 40
+	band := NewSymBand(dim, 1)
+	var expected float64
+	for i := 0; i < dim; i++ {
+		band.SetBand(i, 0, 4)
+		if i+1 < dim {
+			band.SetBand(i, 1, -1)
+		}
+		expected += math.Log(4 - 2*math.Cos(float64(i+1)*math.Pi/(dim+1)))
+	}
+	actual := LogDetSLQ(band, 200, 15, rand.New(rand.NewSource(1)))
+	if math.Abs(actual-expected) > 0.5 {
+		t.Errorf("expected %f but got %f", expected, actual)
+	}
+}
+
+func TestTridiagFirstWeights(t *testing.T) {
+	alphas := linalg.Vector{2, 3, 1, 4}
+	betas := linalg.Vector{1, 0.5, 2}
+	eigs := tridiagEigenvalues(alphas, betas)
+	weights := tridiagFirstWeights(alphas, betas, eigs)
+	var sum, mean float64
+	for i, w := range weights {
+		sum += w
+		mean += w * eigs[i]
+	}
+	// The weights sum to one, and their mean is T[0][0].
+	if math.Abs(sum-1) > 1e-10 || math.Abs(mean-alphas[0]) > 1e-10 {
+		t.Errorf("unexpected weights %v", weights)
+	}
+}
@@ -0,0 +1,68 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// defaultMaxIterFactor is the default iteration limit
+// for SolveLowMem and SolveWithErrorBoundsChecked, as a
+// multiple of the dimension.
+const defaultMaxIterFactor = 10
+
+// SolveLowMem is like Solve, but it keeps as few
+// vectors in memory as CG allows.
+//
+// Exactly four vectors of length t.Dim() are live
+// during the solve: the solution, the residual, the
+// search direction, and the product of t with the
+// search direction.
+// Every update is done in place, so if t is a
+// LinTranInPlace, nothing is allocated after those
+// four vectors are created.
+// Other operators allocate whatever their Apply
+// method does.
+//
+// As with Solve, a prec of 0 is treated as 1e-12 times
+// the largest absolute value of any component of b.
+// Unlike Solve, inner products are not compensated,
+// the residual recurrence is refreshed from b-t*x
+// every 20 iterations, and the solve gives up after
+// 10*t.Dim() iterations.
+func SolveLowMem(t LinTran, b linalg.Vector, prec float64) linalg.Vector {
+	return SolveLowMemStoppable(t, b, prec, nil)
+}
+
+// SolveLowMemStoppable is like SolveLowMem, but it
+// returns the current solution once cancelChan is
+// closed.
+func SolveLowMemStoppable(t LinTran, b linalg.Vector, prec float64,
+	cancelChan <-chan struct{}) linalg.Vector {
+	if len(b) != t.Dim() {
+		panic("dimension mismatch")
+	}
+	if prec == 0 {
+		prec = defaultRelativeTolerance * b.MaxAbs()
+	}
+	maxIter := defaultMaxIterFactor * t.Dim()
+	solution := make(linalg.Vector, len(b))
+	residual := b.Copy()
+	dir := b.Copy()
+	applied := make(linalg.Vector, len(b))
+
+	residualDot := residual.DotFast(residual)
+	for iters := 0; !stopIteration(iters, maxIter, cancelChan) && residual.NormInf() > prec; iters++ {
+		appliedDir := applyInto(t, applied, dir)
+		curvature := dir.DotFast(appliedDir)
+		if curvature == 0 {
+			break
+		}
+		step := residualDot / curvature
+		solution.AddScaled(dir, step)
+		updateResidual(t, b, solution, residual, appliedDir, step, iters,
+			residualUpdateFrequency, applied)
+
+		nextDot := residual.DotFast(residual)
+		dir.Scale(nextDot / residualDot).Add(residual)
+		residualDot = nextDot
+	}
+
+	return solution
+}
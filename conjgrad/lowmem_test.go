@@ -0,0 +1,51 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveLowMem(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	checkSolution(t, SolveLowMem(lt, b, 1e-8), realSolution)
+
+	band, b := lowMemProblem(200)
+	expected := Solve(band, b, 1e-10)
+	checkSolution(t, SolveLowMem(band, b, 1e-10), expected)
+
+	// A prec of 0 must not hang.
+	checkSolution(t, SolveLowMem(band, b, 0), expected)
+	unreachable := SolveLowMem(band, b, -1)
+	if len(unreachable) != len(b) {
+		t.Error("bad solution length", len(unreachable))
+	}
+
+	// The four vectors are the only allocations.
+	allocs := testing.AllocsPerRun(5, func() {
+		SolveLowMem(band, b, 1e-10)
+	})
+	if allocs > 4 {
+		t.Errorf("expected 4 allocations but got %v", allocs)
+	}
+}
+
+func BenchmarkSolveLowMem(b *testing.B) {
+	band, rhs := lowMemProblem(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		SolveLowMem(band, rhs, 1e-8)
+	}
+}
+
+func lowMemProblem(dim int) (*SymBand, linalg.Vector) {
+	band := NewSymBand(dim, 1)
+	for i := 0; i < dim; i++ {
+		band.SetBand(i, 0, 2.5)
+		if i+1 < dim {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	return band, linalg.RandVector(dim)
+}
@@ -0,0 +1,114 @@
+package conjgrad
+
+import (
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// SolveLSQR finds the least-squares solution x to
+// t*x = b using the LSQR method of Paige and Saunders.
+//
+// LSQR is mathematically equivalent to SolveCGNR, but
+// it is based on Golub-Kahan bidiagonalization rather
+// than the normal equations, so it is more stable when
+// t is ill-conditioned.
+// Like SolveCGNR, it only applies t and its transpose.
+// If the system is underdetermined, the minimum-norm
+// solution is found.
+//
+// The solve stops when an estimate of the 2-norm of
+// t'*(b-t*x) drops below prec, or after maxIter
+// iterations if maxIter is positive.
+func SolveLSQR(t LinTranRect, b linalg.Vector, prec float64, maxIter int) linalg.Vector {
+	return SolveLSQRDampedStoppable(t, b, 0, prec, maxIter, nil)
+}
+
+// SolveLSQRStoppable is like SolveLSQR, but it returns
+// the current solution once cancelChan is closed.
+//
+// This is the only way to bound an LSQR solve whose
+// prec cannot be met when maxIter is not positive.
+func SolveLSQRStoppable(t LinTranRect, b linalg.Vector, prec float64, maxIter int,
+	cancelChan <-chan struct{}) linalg.Vector {
+	return SolveLSQRDampedStoppable(t, b, 0, prec, maxIter, cancelChan)
+}
+
+// SolveLSQRDamped is like SolveLSQR, but it minimizes
+// ||t*x-b||^2 + damp^2*||x||^2, which is the least
+// squares problem with Tikhonov regularization.
+//
+// With damping, the prec bound applies to the gradient
+// of the regularized objective.
+func SolveLSQRDamped(t LinTranRect, b linalg.Vector, damp, prec float64,
+	maxIter int) linalg.Vector {
+	return SolveLSQRDampedStoppable(t, b, damp, prec, maxIter, nil)
+}
+
+// SolveLSQRDampedStoppable is like SolveLSQRDamped,
+// but it returns the current solution once cancelChan
+// is closed.
+func SolveLSQRDampedStoppable(t LinTranRect, b linalg.Vector, damp, prec float64,
+	maxIter int, cancelChan <-chan struct{}) linalg.Vector {
+	if len(b) != t.Rows() {
+		panic("dimension mismatch")
+	}
+	solution := make(linalg.Vector, t.Cols())
+
+	u := b.Copy()
+	beta := u.Mag()
+	if beta == 0 {
+		return solution
+	}
+	u.Scale(1 / beta)
+	v := t.ApplyTranspose(u)
+	alpha := v.Mag()
+	if alpha == 0 {
+		return solution
+	}
+	v.Scale(1 / alpha)
+
+	dir := v.Copy()
+	phiBar := beta
+	rhoBar := alpha
+
+	for i := 0; !stopIteration(i, maxIter, cancelChan); i++ {
+		// Continue the bidiagonalization.
+		u = t.Apply(v).AddScaled(u, -alpha)
+		beta = u.Mag()
+		if beta != 0 {
+			u.Scale(1 / beta)
+			v = t.ApplyTranspose(u).AddScaled(v, -beta)
+			alpha = v.Mag()
+			if alpha != 0 {
+				v.Scale(1 / alpha)
+			}
+		}
+
+		// Eliminate the damping term.
+		rhoBar1 := rhoBar
+		if damp != 0 {
+			c1, s1 := NewGivens(rhoBar, damp)
+			rhoBar1, _ = ApplyGivens(c1, s1, rhoBar, damp)
+			phiBar *= c1
+		}
+
+		// Eliminate the subdiagonal of the bidiagonal matrix.
+		c, s := NewGivens(rhoBar1, beta)
+		rho, _ := ApplyGivens(c, s, rhoBar1, beta)
+		theta := s * alpha
+		rhoBar = -c * alpha
+		phi := c * phiBar
+		phiBar *= s
+
+		solution.AddScaled(dir, phi/rho)
+		dir.Scale(-theta / rho).Add(v)
+
+		normalResidual := phiBar * alpha * math.Abs(c)
+		if normalResidual <= prec || beta == 0 || alpha == 0 {
+			break
+		}
+	}
+
+	return solution
+}
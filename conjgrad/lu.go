@@ -0,0 +1,105 @@
+package conjgrad
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// LUFactor is the LU factorization P*A = L*U of a
+// square matrix A, computed with partial pivoting.
+type LUFactor struct {
+	// lu stores U in its upper triangle and the
+	// subdiagonal entries of L (whose diagonal is all
+	// ones) in its lower triangle.
+	lu *DenseMatrix
+
+	// perm[i] is the row of A which became row i.
+	perm []int
+
+	// sign is the determinant of P.
+	sign float64
+}
+
+// LU computes the LU factorization of a square matrix
+// using Gaussian elimination with partial pivoting.
+//
+// An error is returned if a is not square or if it is
+// singular, meaning that a zero pivot remains even
+// after pivoting.
+func LU(a *DenseMatrix) (*LUFactor, error) {
+	if a.Rows != a.Cols {
+		return nil, errors.New("matrix is not square")
+	}
+	n := a.Rows
+	lu := &DenseMatrix{Rows: n, Cols: n, Data: append([]float64{}, a.Data...)}
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	sign := 1.0
+
+	for col := 0; col < n; col++ {
+		pivotRow := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(lu.At(row, col)) > math.Abs(lu.At(pivotRow, col)) {
+				pivotRow = row
+			}
+		}
+		pivot := lu.At(pivotRow, col)
+		if pivot == 0 {
+			return nil, fmt.Errorf("matrix is singular (zero pivot in column %d)", col)
+		}
+		if pivotRow != col {
+			rowA := lu.Data[col*n : (col+1)*n]
+			rowB := lu.Data[pivotRow*n : (pivotRow+1)*n]
+			for i := range rowA {
+				rowA[i], rowB[i] = rowB[i], rowA[i]
+			}
+			perm[col], perm[pivotRow] = perm[pivotRow], perm[col]
+			sign = -sign
+		}
+
+		pivotTail := linalg.Vector(lu.Data[col*n+col+1 : (col+1)*n])
+		for row := col + 1; row < n; row++ {
+			scale := lu.At(row, col) / pivot
+			lu.Set(row, col, scale)
+			linalg.Vector(lu.Data[row*n+col+1:(row+1)*n]).AddScaled(pivotTail, -scale)
+		}
+	}
+
+	return &LUFactor{lu: lu, perm: perm, sign: sign}, nil
+}
+
+// Solve solves A*x = b using forward and back
+// substitution.
+func (l *LUFactor) Solve(b linalg.Vector) linalg.Vector {
+	n := l.lu.Rows
+	if len(b) != n {
+		panic("dimension mismatch")
+	}
+	res := make(linalg.Vector, n)
+	for i, row := range l.perm {
+		res[i] = b[row]
+	}
+	for i := 0; i < n; i++ {
+		row := linalg.Vector(l.lu.Data[i*n : i*n+i])
+		res[i] -= row.DotFast(res[:i])
+	}
+	for i := n - 1; i >= 0; i-- {
+		row := linalg.Vector(l.lu.Data[i*n+i+1 : (i+1)*n])
+		res[i] = (res[i] - row.DotFast(res[i+1:])) / l.lu.At(i, i)
+	}
+	return res
+}
+
+// Det returns the determinant of A.
+func (l *LUFactor) Det() float64 {
+	det := l.sign
+	for i := 0; i < l.lu.Rows; i++ {
+		det *= l.lu.At(i, i)
+	}
+	return det
+}
@@ -0,0 +1,38 @@
+package conjgrad
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLU(t *testing.T) {
+	lt, b, realSolution := nonSymmetricProblem()
+	dense := &DenseMatrix{Rows: lt.M.Rows, Cols: lt.M.Cols, Data: lt.M.Data}
+	factor, err := LU(dense)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, factor.Solve(b), realSolution)
+
+	// This matrix needs a row swap.
+	dense = &DenseMatrix{Rows: 3, Cols: 3, Data: []float64{
+		0, 2, 1,
+		1, 1, 1,
+		2, 1, 3,
+	}}
+	factor, err = LU(dense)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if det := factor.Det(); math.Abs(det+3) > 1e-12 {
+		t.Errorf("expected determinant -3 but got %f", det)
+	}
+	checkSolution(t, dense.Apply(factor.Solve([]float64{1, 2, 3})), []float64{1, 2, 3})
+}
+
+func TestLUSingular(t *testing.T) {
+	dense := &DenseMatrix{Rows: 2, Cols: 2, Data: []float64{1, 2, 2, 4}}
+	if _, err := LU(dense); err == nil {
+		t.Error("expected error for singular matrix")
+	}
+}
@@ -0,0 +1,154 @@
+package conjgrad
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadMatrixMarket parses a square real matrix in the
+// Matrix Market exchange format and returns it as a
+// *SparseCSR.
+//
+// Both the coordinate and array formats are supported,
+// with real or integer entries.
+// For symmetric matrices, only the lower triangle is
+// stored in the file, and the upper triangle is filled
+// in by symmetry.
+// Complex, pattern, skew-symmetric, and Hermitian
+// matrices are not supported.
+func ReadMatrixMarket(r io.Reader) (LinTran, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("matrix market: empty input")
+	}
+	header := strings.Fields(strings.ToLower(scanner.Text()))
+	if len(header) != 5 || header[0] != "%%matrixmarket" || header[1] != "matrix" {
+		return nil, errors.New("matrix market: invalid header")
+	}
+	format, field, symmetry := header[2], header[3], header[4]
+	if format != "coordinate" && format != "array" {
+		return nil, fmt.Errorf("matrix market: unsupported format %q", format)
+	}
+	if field != "real" && field != "integer" {
+		return nil, fmt.Errorf("matrix market: unsupported field %q", field)
+	}
+	if symmetry != "general" && symmetry != "symmetric" {
+		return nil, fmt.Errorf("matrix market: unsupported symmetry %q", symmetry)
+	}
+	symmetric := symmetry == "symmetric"
+
+	lineNum := 1
+	nextLine := func() ([]string, error) {
+		for scanner.Scan() {
+			lineNum++
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "%") {
+				continue
+			}
+			return strings.Fields(line), nil
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+	parseInts := func(fields []string) ([]int, error) {
+		res := make([]int, len(fields))
+		for i, f := range fields {
+			n, err := strconv.Atoi(f)
+			if err != nil {
+				return nil, fmt.Errorf("matrix market: line %d: %v", lineNum, err)
+			}
+			res[i] = n
+		}
+		return res, nil
+	}
+
+	sizeLine, err := nextLine()
+	if err != nil {
+		return nil, err
+	}
+	if (format == "coordinate" && len(sizeLine) != 3) ||
+		(format == "array" && len(sizeLine) != 2) {
+		return nil, fmt.Errorf("matrix market: line %d: invalid size line", lineNum)
+	}
+	sizes, err := parseInts(sizeLine)
+	if err != nil {
+		return nil, err
+	}
+	rows, cols := sizes[0], sizes[1]
+	if rows != cols || rows <= 0 {
+		return nil, fmt.Errorf("matrix market: matrix is %dx%d, not square", rows, cols)
+	}
+
+	var entries []cooEntry
+	addEntry := func(row, col int, val float64) {
+		entries = append(entries, cooEntry{row: row, col: col, val: val})
+		if symmetric && row != col {
+			entries = append(entries, cooEntry{row: col, col: row, val: val})
+		}
+	}
+
+	if format == "coordinate" {
+		for i := 0; i < sizes[2]; i++ {
+			fields, err := nextLine()
+			if err != nil {
+				return nil, err
+			}
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("matrix market: line %d: expected 3 fields", lineNum)
+			}
+			indices, err := parseInts(fields[:2])
+			if err != nil {
+				return nil, err
+			}
+			row, col := indices[0]-1, indices[1]-1
+			if row < 0 || row >= rows || col < 0 || col >= cols {
+				return nil, fmt.Errorf("matrix market: line %d: index out of bounds", lineNum)
+			}
+			if symmetric && col > row {
+				return nil, fmt.Errorf("matrix market: line %d: entry above diagonal "+
+					"in symmetric matrix", lineNum)
+			}
+			val, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("matrix market: line %d: %v", lineNum, err)
+			}
+			addEntry(row, col, val)
+		}
+	} else {
+		// Array entries are listed in column-major order.
+		for col := 0; col < cols; col++ {
+			startRow := 0
+			if symmetric {
+				startRow = col
+			}
+			for row := startRow; row < rows; row++ {
+				fields, err := nextLine()
+				if err != nil {
+					return nil, err
+				}
+				if len(fields) != 1 {
+					return nil, fmt.Errorf("matrix market: line %d: expected 1 field", lineNum)
+				}
+				val, err := strconv.ParseFloat(fields[0], 64)
+				if err != nil {
+					return nil, fmt.Errorf("matrix market: line %d: %v", lineNum, err)
+				}
+				if val != 0 {
+					addEntry(row, col, val)
+				}
+			}
+		}
+	}
+
+	return newSparseCSR(rows, entries), nil
+}
@@ -0,0 +1,80 @@
+package conjgrad
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestReadMatrixMarket(t *testing.T) {
+	inputs := []string{
+		`%%MatrixMarket matrix coordinate real symmetric
+% The 3x3 tridiagonal matrix [2 -1 0; -1 2 -1; 0 -1 2].
+3 3 5
+1 1 2
+2 1 -1
+2 2 2
+3 2 -1
+3 3 2.0
+`,
+		`%%MatrixMarket matrix coordinate integer general
+3 3 7
+1 1 2
+1 2 -1
+2 1 -1
+2 2 2
+2 3 -1
+3 2 -1
+3 3 2
+`,
+		`%%MatrixMarket matrix array real general
+3 3
+2
+-1
+0
+-1
+2
+-1
+0
+-1
+2
+`,
+		`%%MatrixMarket matrix array real symmetric
+3 3
+2
+-1
+0
+2
+-1
+2
+`,
+	}
+	vec := linalg.Vector{1, 2, 3}
+	expected := linalg.Vector{0, 0, 4}
+	for i, input := range inputs {
+		mat, err := ReadMatrixMarket(strings.NewReader(input))
+		if err != nil {
+			t.Errorf("input %d: %v", i, err)
+			continue
+		}
+		checkSolution(t, mat.Apply(vec), expected)
+	}
+}
+
+func TestReadMatrixMarketErrors(t *testing.T) {
+	inputs := []string{
+		"",
+		"%%MatrixMarket matrix coordinate complex general\n1 1 1\n1 1 1 0\n",
+		"%%MatrixMarket matrix coordinate pattern general\n1 1 1\n1 1\n",
+		"%%MatrixMarket matrix coordinate real general\n2 3 0\n",
+		"%%MatrixMarket matrix coordinate real general\n2 2 2\n1 1 1\n",
+		"%%MatrixMarket matrix coordinate real general\n2 2 1\n3 1 1\n",
+		"%%MatrixMarket matrix coordinate real symmetric\n2 2 1\n1 2 1\n",
+	}
+	for i, input := range inputs {
+		if _, err := ReadMatrixMarket(strings.NewReader(input)); err == nil {
+			t.Errorf("input %d: expected an error", i)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package conjgrad
+
+import (
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// SolveMINRES solves a system of linear equations
+// t*x = b for x using the minimal residual method.
+//
+// Unlike SolveStoppable, this only assumes that t
+// is symmetric; t may be indefinite.
+// The 2-norm of the residual decreases monotonically
+// from one iteration to the next.
+//
+// The solve stops once the 2-norm of the residual,
+// which MINRES tracks at no extra cost, is no greater
+// than prec.
+// This also bounds the largest component of the
+// residual.
+// The cancelChan argument behaves as it does for
+// SolveStoppable.
+func SolveMINRES(t LinTran, b linalg.Vector, prec float64,
+	cancelChan <-chan struct{}) linalg.Vector {
+	return SolveMINRESDetailed(t, b, prec, cancelChan).Solution
+}
+
+// SolveMINRESDetailed is like SolveMINRES, but it
+// returns a SolveResult.
+func SolveMINRESDetailed(t LinTran, b linalg.Vector, prec float64,
+	cancelChan <-chan struct{}) SolveResult {
+	solution := make(linalg.Vector, t.Dim())
+	if b.Mag() <= prec {
+		return detailedResult(t, b, solution, 0, prec)
+	}
+
+	// This follows the Lanczos-based formulation
+	// of Paige and Saunders.
+	lastLanczos := b.Copy()
+	lanczos := b.Copy()
+	next := b.Copy()
+	beta := b.Mag()
+	var lastBeta, dbar, epsilon float64
+	residualNorm := beta
+	cs, sn := -1.0, 0.0
+
+	dir := make(linalg.Vector, t.Dim())
+	lastDir := make(linalg.Vector, t.Dim())
+
+	var iters int
+	for ; residualNorm > prec && beta != 0; iters++ {
+		v := next.Scale(1 / beta)
+		next = t.Apply(v)
+		if iters > 0 {
+			next.Add(lastLanczos.Copy().Scale(-beta / lastBeta))
+		}
+		alpha := v.Dot(next)
+		next.Add(lanczos.Copy().Scale(-alpha / beta))
+		lastLanczos, lanczos = lanczos, next.Copy()
+		lastBeta, beta = beta, next.Mag()
+
+		lastEpsilon := epsilon
+		delta := cs*dbar + sn*alpha
+		gbar := sn*dbar - cs*alpha
+		epsilon = sn * beta
+		dbar = -cs * beta
+		cs, sn = NewGivens(gbar, beta)
+		gamma, _ := ApplyGivens(cs, sn, gbar, beta)
+		gamma = math.Max(gamma, math.SmallestNonzeroFloat64)
+		phi := cs * residualNorm
+		residualNorm *= sn
+
+		// dir = (v - epsilon*dir2 - delta*dir1) / gamma
+		olderDir := lastDir
+		lastDir = dir
+		dir = olderDir.Scale(-lastEpsilon).Add(lastDir.Copy().Scale(-delta)).Add(v)
+		dir.Scale(1 / gamma)
+		solution.Add(dir.Copy().Scale(phi))
+
+		if cancelled(cancelChan) {
+			iters++
+			break
+		}
+	}
+
+	return detailedResult(t, b, solution, iters, prec)
+}
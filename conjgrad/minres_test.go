@@ -0,0 +1,41 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveMINRES(t *testing.T) {
+	lt, b, realSolution := indefiniteProblem()
+	solution := SolveMINRES(lt, b, 1e-10, nil)
+	checkSolution(t, solution, realSolution)
+
+	lt1, b1, realSolution1 := testProblem()
+	solution = SolveMINRES(lt1, b1, 1e-9, nil)
+	checkSolution(t, solution, realSolution1)
+
+	// The bound applies to the 2-norm, even when every
+	// component is within prec.
+	res := SolveMINRESDetailed(Diagonal{1, 2}, linalg.Vector{1, 1}, 1.2, nil)
+	if res.Iterations == 0 {
+		t.Error("expected MINRES to iterate when the 2-norm exceeds prec")
+	}
+}
+
+func indefiniteProblem() (lt MatLinTran, b, solution linalg.Vector) {
+	problem := &linalg.Matrix{
+		Rows: 4,
+		Cols: 4,
+		Data: []float64{
+			2, 1, 0, 0,
+			1, -3, 1, 0,
+			0, 1, 1, 2,
+			0, 0, 2, -1,
+		},
+	}
+	lt = MatLinTran{M: problem}
+	solution = linalg.Vector{1, -2, 3, 0.5}
+	b = lt.Apply(solution)
+	return
+}
@@ -0,0 +1,189 @@
+// Package multigrid implements geometric multigrid
+// V-cycles for large sparse systems such as
+// discretized Poisson problems.
+//
+// Each level of a hierarchy has its own operator, and
+// restrictions and prolongations move vectors between
+// neighboring levels.
+package multigrid
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/unixpickle/num-analysis/conjgrad"
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// A Smoother improves an approximate solution x to
+// a*x = b in place, typically by damping its high
+// frequency error with a few relaxation sweeps.
+type Smoother func(a conjgrad.LinTran, x, b linalg.Vector)
+
+// GaussSeidelSmoother returns a Smoother which runs
+// the given number of Gauss-Seidel sweeps.
+//
+// Like conjgrad.SolveGaussSeidel, it needs the entries
+// of the operator, so it panics unless the operator is
+// a *conjgrad.DenseMatrix, *conjgrad.SparseCSR, or
+// *conjgrad.SymBand.
+func GaussSeidelSmoother(sweeps int) Smoother {
+	return SORSmoother(1, sweeps)
+}
+
+// SORSmoother is like GaussSeidelSmoother, but it uses
+// successive over-relaxation with the factor omega.
+func SORSmoother(omega float64, sweeps int) Smoother {
+	return func(a conjgrad.LinTran, x, b linalg.Vector) {
+		// A sweep on the residual equation a*e = b-a*x,
+		// starting from e = 0, is a sweep on x itself.
+		residual := b.Copy().Sub(a.Apply(x))
+		correction, err := conjgrad.SolveSOR(a, residual, omega, 0, sweeps)
+		if err != nil {
+			panic(err)
+		}
+		x.Add(correction)
+	}
+}
+
+// VCycle is a multigrid hierarchy which solves systems
+// with V-cycles.
+type VCycle struct {
+	operators     []conjgrad.LinTran
+	restrictions  []conjgrad.LinTranRect
+	prolongations []conjgrad.LinTranRect
+	smoother      Smoother
+	coarse        *conjgrad.LUFactor
+}
+
+// NewVCycle creates a multigrid hierarchy.
+//
+// The operators go from the finest level to the
+// coarsest.
+// For every level i but the coarsest, restrictions[i]
+// maps vectors from level i to level i+1, and
+// prolongations[i] maps them back.
+// If prolongations is nil, the transposes of the
+// restrictions are used instead.
+//
+// The smoother runs once before and once after each
+// coarse-grid correction.
+// If it is nil, GaussSeidelSmoother(1) is used.
+// The coarsest system is solved directly with an LU
+// factorization, which is computed here.
+//
+// This panics if the dimensions do not line up, and an
+// error is returned if the coarsest operator is
+// singular.
+func NewVCycle(operators []conjgrad.LinTran, restrictions,
+	prolongations []conjgrad.LinTranRect, smoother Smoother) (*VCycle, error) {
+	if len(operators) == 0 {
+		panic("no operators")
+	}
+	if len(restrictions) != len(operators)-1 ||
+		(prolongations != nil && len(prolongations) != len(restrictions)) {
+		panic("wrong number of transfer operators")
+	}
+	for i, r := range restrictions {
+		if r.Cols() != operators[i].Dim() || r.Rows() != operators[i+1].Dim() {
+			panic(fmt.Sprintf("restriction %d: dimension mismatch", i))
+		}
+		if prolongations != nil {
+			p := prolongations[i]
+			if p.Rows() != r.Cols() || p.Cols() != r.Rows() {
+				panic(fmt.Sprintf("prolongation %d: dimension mismatch", i))
+			}
+		}
+	}
+	if smoother == nil {
+		smoother = GaussSeidelSmoother(1)
+	}
+
+	coarsest := operators[len(operators)-1]
+	dense := conjgrad.NewDenseMatrix(coarsest.Dim(), coarsest.Dim())
+	basis := make(linalg.Vector, coarsest.Dim())
+	for j := range basis {
+		basis[j] = 1
+		for i, x := range coarsest.Apply(basis) {
+			dense.Set(i, j, x)
+		}
+		basis[j] = 0
+	}
+	coarse, err := conjgrad.LU(dense)
+	if err != nil {
+		return nil, errors.New("coarsest operator: " + err.Error())
+	}
+
+	return &VCycle{
+		operators:     operators,
+		restrictions:  restrictions,
+		prolongations: prolongations,
+		smoother:      smoother,
+		coarse:        coarse,
+	}, nil
+}
+
+// Cycle performs one V-cycle, improving an
+// approximate solution x of the finest system in
+// place.
+func (v *VCycle) Cycle(x, b linalg.Vector) {
+	if len(x) != v.operators[0].Dim() || len(b) != len(x) {
+		panic("dimension mismatch")
+	}
+	v.cycle(0, x, b)
+}
+
+// Solve solves the finest system for b by repeating
+// V-cycles from a zero initial guess.
+//
+// It stops when no component of the residual exceeds
+// prec, or when a cycle fails to shrink the residual.
+func (v *VCycle) Solve(b linalg.Vector, prec float64) linalg.Vector {
+	return v.SolveStoppable(b, prec, nil)
+}
+
+// SolveStoppable is like Solve, but it returns the
+// current solution once cancelChan is closed.
+// The channel is checked before each V-cycle.
+func (v *VCycle) SolveStoppable(b linalg.Vector, prec float64,
+	cancelChan <-chan struct{}) linalg.Vector {
+	x := make(linalg.Vector, len(b))
+	residual := b.Copy()
+	lastNorm := residual.NormInf()
+	for lastNorm > prec {
+		select {
+		case <-cancelChan:
+			return x
+		default:
+		}
+		v.Cycle(x, b)
+		residual = b.Copy().Sub(v.operators[0].Apply(x))
+		norm := residual.NormInf()
+		if !(norm < lastNorm) {
+			break
+		}
+		lastNorm = norm
+	}
+	return x
+}
+
+func (v *VCycle) cycle(level int, x, b linalg.Vector) {
+	if level == len(v.operators)-1 {
+		copy(x, v.coarse.Solve(b))
+		return
+	}
+	a := v.operators[level]
+	v.smoother(a, x, b)
+
+	residual := b.Copy().Sub(a.Apply(x))
+	coarseB := v.restrictions[level].Apply(residual)
+	coarseX := make(linalg.Vector, len(coarseB))
+	v.cycle(level+1, coarseX, coarseB)
+	if v.prolongations != nil {
+		x.Add(v.prolongations[level].Apply(coarseX))
+	} else {
+		x.Add(v.restrictions[level].ApplyTranspose(coarseX))
+	}
+
+	v.smoother(a, x, b)
+}
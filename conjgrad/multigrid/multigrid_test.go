@@ -0,0 +1,73 @@
+package multigrid
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/conjgrad"
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// poissonHierarchy creates the 1D Poisson operators on
+// grids of 2^k-1 interior points, along with linear
+// interpolation and full-weighting restriction.
+func poissonHierarchy(levels int) ([]conjgrad.LinTran, []conjgrad.LinTranRect,
+	[]conjgrad.LinTranRect) {
+	var operators []conjgrad.LinTran
+	var restrictions, prolongations []conjgrad.LinTranRect
+	dim := 1<<uint(levels+1) - 1
+	for level := 0; level < levels; level++ {
+		h := 1 / float64(dim+1)
+		op := conjgrad.NewSymBand(dim, 1)
+		for i := 0; i < dim; i++ {
+			op.SetBand(i, 0, 2/(h*h))
+			if i+1 < dim {
+				op.SetBand(i, 1, -1/(h*h))
+			}
+		}
+		operators = append(operators, op)
+		if level+1 == levels {
+			break
+		}
+		coarseDim := (dim - 1) / 2
+		prolong := linalg.NewMatrix(dim, coarseDim)
+		for j := 0; j < coarseDim; j++ {
+			prolong.Set(2*j, j, 0.5)
+			prolong.Set(2*j+1, j, 1)
+			prolong.Set(2*j+2, j, 0.5)
+		}
+		restrict := prolong.Transpose().Scale(0.5)
+		prolongations = append(prolongations, conjgrad.MatLinTran{M: prolong})
+		restrictions = append(restrictions, conjgrad.MatLinTran{M: restrict})
+		dim = coarseDim
+	}
+	return operators, restrictions, prolongations
+}
+
+func TestVCycle(t *testing.T) {
+	operators, restrictions, prolongations := poissonHierarchy(5)
+	for _, smoother := range []Smoother{nil, SORSmoother(1.2, 2)} {
+		v, err := NewVCycle(operators, restrictions, prolongations, smoother)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := linalg.RandVector(operators[0].Dim())
+		b := operators[0].Apply(expected)
+		x := make(linalg.Vector, len(b))
+		for i := 0; i < 15; i++ {
+			v.Cycle(x, b)
+		}
+		if !x.ApproxEqual(expected, 1e-6) {
+			t.Error("V-cycles did not converge")
+		}
+		solution := v.Solve(b, 1e-10*b.NormInf())
+		if !solution.ApproxEqual(expected, 1e-6) {
+			t.Error("unexpected solution from Solve")
+		}
+
+		cancelChan := make(chan struct{})
+		close(cancelChan)
+		if v.SolveStoppable(b, 0, cancelChan).NormInf() != 0 {
+			t.Error("expected zero solution after cancellation")
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// ncgCurvature is the Wolfe constant used for the
+// line searches in MinimizeNCG.
+const ncgCurvature = 0.1
+
+// MinimizeNCG minimizes a smooth function f, starting
+// from x0, using the Polak-Ribiere variant of
+// nonlinear conjugate gradients.
+//
+// Each search direction is combined with the previous
+// one just like in linear CG, and the step length is
+// found with a line search which enforces the
+// strong Wolfe conditions.
+// If a direction fails to point downhill, the method
+// restarts with steepest descent.
+//
+// The minimization stops once no component of the
+// gradient exceeds prec, or once the line search can
+// no longer make progress.
+func MinimizeNCG(f func(linalg.Vector) float64, grad func(linalg.Vector) linalg.Vector,
+	x0 linalg.Vector, prec float64) linalg.Vector {
+	x := x0.Copy()
+	gradient := grad(x)
+	dir := gradient.ScaledCopy(-1)
+	initialStep := 1.0
+	var lastSlope float64
+	for gradient.NormInf() > prec {
+		slope := dir.Dot(gradient)
+		if slope >= 0 {
+			dir = gradient.ScaledCopy(-1)
+			slope = dir.Dot(gradient)
+		}
+		if lastSlope != 0 {
+			// Expect the same first-order decrease as the
+			// last step achieved.
+			initialStep *= lastSlope / slope
+		}
+		step := LineSearchWith(f, grad, x, dir, &LineSearchOptions{
+			Wolfe:       ncgCurvature,
+			InitialStep: initialStep,
+		})
+		next := x.Copy().AddScaled(dir, step)
+		if f(next) >= f(x) {
+			break
+		}
+		x = next
+		initialStep, lastSlope = step, slope
+
+		nextGradient := grad(x)
+		beta := nextGradient.Dot(nextGradient.Copy().Sub(gradient)) / gradient.Dot(gradient)
+		if beta < 0 {
+			beta = 0
+		}
+		dir.Scale(beta).AddScaled(nextGradient, -1)
+		gradient = nextGradient
+	}
+	return x
+}
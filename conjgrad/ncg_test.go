@@ -0,0 +1,40 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestMinimizeNCG(t *testing.T) {
+	// The Rosenbrock function has its minimum at (1, 1).
+	f := func(x linalg.Vector) float64 {
+		a, b := 1-x[0], x[1]-x[0]*x[0]
+		return a*a + 100*b*b
+	}
+	grad := func(x linalg.Vector) linalg.Vector {
+		b := x[1] - x[0]*x[0]
+		return linalg.Vector{-2*(1-x[0]) - 400*x[0]*b, 200 * b}
+	}
+	solution := MinimizeNCG(f, grad, linalg.Vector{-1.2, 1}, 1e-8)
+	checkSolution(t, solution, linalg.Vector{1, 1})
+
+	// For a quadratic, the minimum solves the linear system.
+	band := NewSymBand(20, 1)
+	for i := 0; i < 20; i++ {
+		band.SetBand(i, 0, 2.5)
+		if i+1 < 20 {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	expected := linalg.RandVector(20)
+	b := band.Apply(expected)
+	quadratic := func(x linalg.Vector) float64 {
+		return QuadraticForm(band, x, b)
+	}
+	quadGrad := func(x linalg.Vector) linalg.Vector {
+		return band.Apply(x).Sub(b)
+	}
+	solution = MinimizeNCG(quadratic, quadGrad, make(linalg.Vector, 20), 1e-9)
+	checkSolution(t, solution, expected)
+}
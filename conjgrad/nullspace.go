@@ -0,0 +1,57 @@
+package conjgrad
+
+import (
+	"fmt"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// SolveNullspaceFiltered solves a consistent singular
+// system t*x = b, where t is symmetric positive
+// semidefinite with the given null space.
+//
+// The nullBasis vectors must be an orthonormal basis
+// for the null space of t.
+// Both b and every residual are projected onto the
+// complement of the null space, so CG does not wander
+// along it, and the returned x is the minimum-norm
+// solution.
+//
+// If b has a component in the null space greater than
+// prec, the system has no solution; in that case the
+// projected system is still solved, but an error is
+// returned alongside the result.
+func SolveNullspaceFiltered(t LinTran, b linalg.Vector, nullBasis []linalg.Vector,
+	prec float64) (linalg.Vector, error) {
+	return SolveNullspaceFilteredStoppable(t, b, nullBasis, prec, nil)
+}
+
+// SolveNullspaceFilteredStoppable is like
+// SolveNullspaceFiltered, but it returns the current
+// solution once cancelChan is closed.
+func SolveNullspaceFilteredStoppable(t LinTran, b linalg.Vector, nullBasis []linalg.Vector,
+	prec float64, cancelChan <-chan struct{}) (linalg.Vector, error) {
+	projector := nullspaceProjector(nullBasis)
+	projected := projector.ApplyInverse(b)
+
+	var err error
+	if outside := b.Copy().Sub(projected).NormInf(); outside > prec {
+		err = fmt.Errorf("right-hand side is not in the range space "+
+			"(null space component %g)", outside)
+	}
+
+	solution := SolvePreconditioned(t, projector, projected, prec, cancelChan)
+	return projector.ApplyInverse(solution), err
+}
+
+// nullspaceProjector is a Preconditioner which
+// projects vectors orthogonally to its basis.
+type nullspaceProjector []linalg.Vector
+
+func (n nullspaceProjector) ApplyInverse(r linalg.Vector) linalg.Vector {
+	res := r.Copy()
+	for _, vec := range n {
+		res.AddScaled(vec, -vec.Dot(res))
+	}
+	return res
+}
@@ -0,0 +1,47 @@
+package conjgrad
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveNullspaceFiltered(t *testing.T) {
+	// A 1D Laplacian with Neumann boundaries, whose null
+	// space is spanned by the constant vector.
+	const dim = 10
+	band := NewSymBand(dim, 1)
+	for i := 0; i < dim; i++ {
+		diag := 2.0
+		if i == 0 || i == dim-1 {
+			diag = 1
+		}
+		band.SetBand(i, 0, diag)
+		if i+1 < dim {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	constant := make(linalg.Vector, dim)
+	for i := range constant {
+		constant[i] = 1 / math.Sqrt(dim)
+	}
+	nullBasis := []linalg.Vector{constant}
+
+	expected := linalg.RandVector(dim)
+	expected.AddScaled(constant, -constant.Dot(expected))
+	b := band.Apply(expected)
+
+	solution, err := SolveNullspaceFiltered(band, b, nullBasis, 1e-10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, solution, expected)
+
+	b.AddScaled(constant, 1)
+	solution, err = SolveNullspaceFiltered(band, b, nullBasis, 1e-10)
+	if err == nil {
+		t.Error("expected an error for an inconsistent system")
+	}
+	checkSolution(t, solution, expected)
+}
@@ -0,0 +1,174 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// ResidualNorm selects the norm which is compared to
+// the tolerance to decide when a solve is complete.
+type ResidualNorm int
+
+const (
+	// NormInf measures the largest absolute value of
+	// any component of the residual.
+	NormInf ResidualNorm = iota
+
+	// Norm2 measures the Euclidean norm of the
+	// residual.
+	Norm2
+
+	// NormRelative measures the largest component of
+	// the residual relative to the same component of
+	// the right-hand side, as computed by
+	// linalg.Vector.MaxAbsRelative.
+	// This suits systems whose right-hand sides span
+	// many orders of magnitude.
+	NormRelative
+)
+
+// SolveOptions configures a solve performed
+// with SolveWith.
+//
+// The options only apply to the CG solver; see
+// SolveWith.
+//
+// The zero value runs unpreconditioned CG from a zero
+// initial guess until the residual is exactly zero.
+type SolveOptions struct {
+	// Tolerance is a bound on the residual error.
+	// Once the largest element of (Ax-b) has an
+	// absolute value no greater than Tolerance,
+	// the solve is complete.
+	Tolerance float64
+
+	// RelativeTolerance is a bound on the residual
+	// error relative to the largest absolute value of
+	// any component of b.
+	// If both tolerances are set, the solve stops once
+	// either one is met.
+	RelativeTolerance float64
+
+	// ResidualNorm is the norm of (Ax-b) which is
+	// compared to Tolerance.
+	// It only affects the stopping test.
+	// The default is NormInf.
+	ResidualNorm ResidualNorm
+
+	// MaxIter is the maximum number of iterations
+	// to run before giving up.
+	// If it is 0 or negative, there is no limit.
+	MaxIter int
+
+	// FastDot, if true, makes the solver compute inner
+	// products with linalg.Vector.DotFast instead of the
+	// compensated linalg.Vector.DotKahan.
+	//
+	// Compensated summation helps to keep the search
+	// directions conjugate for very large systems, but
+	// it is roughly four times slower.
+	FastDot bool
+
+	// StagnationWindow, if positive, enables
+	// stagnation detection.
+	// If the 2-norm of the residual fails to shrink by
+	// a relative amount of more than StagnationTol over
+	// StagnationWindow consecutive iterations, the
+	// solve stops and SolveResult.Stagnated is set.
+	//
+	// The CG residual does not decrease monotonically,
+	// so very small windows may flag stagnation early.
+	StagnationWindow int
+
+	// StagnationTol is the relative improvement that
+	// is expected over StagnationWindow iterations.
+	StagnationTol float64
+
+	// ResidualRefreshInterval is the number of
+	// iterations between recomputations of the true
+	// residual b-Ax, which is otherwise updated with a
+	// cheaper recurrence that slowly accumulates
+	// rounding error.
+	// Very ill-conditioned systems may need a smaller
+	// interval to keep converging.
+	// If it is 0, the default of 20 is used.
+	// If it is negative, the residual is never
+	// recomputed.
+	ResidualRefreshInterval int
+
+	// RestartInterval, if positive, is the number of
+	// iterations after which the search direction is
+	// reset to the current (preconditioned) residual.
+	// A restart discards the conjugacy with earlier
+	// directions, which rounding errors may have
+	// corrupted, and rebuilds it from scratch.
+	// If it is 0 or negative, CG never restarts.
+	RestartInterval int
+
+	// DetectResidualFloor, if true, stops the solve
+	// once the true residual, recomputed every
+	// ResidualRefreshInterval iterations, fails to
+	// improve on its previous value by at least 1%.
+	// The solve then sets SolveResult.FloorReached,
+	// since the Tolerance is most likely below the
+	// accuracy attainable for this conditioning.
+	//
+	// This is useful with a Tolerance of 0, to get a
+	// solution which is as accurate as possible
+	// without running out the iteration budget.
+	// It has no effect if the residual is never
+	// recomputed.
+	DetectResidualFloor bool
+
+	// Preconditioner, if non-nil, is used to
+	// precondition the solve.
+	Preconditioner Preconditioner
+
+	// InitialGuess, if non-nil, is the starting point
+	// of the solve.
+	// Its length must match t.Dim(), and it is not
+	// modified.
+	InitialGuess linalg.Vector
+
+	// Observe, if non-nil, is called after every
+	// iteration with the number of iterations so far
+	// and the largest absolute value of any component
+	// of the residual.
+	// If it returns false, the solve is stopped.
+	Observe func(iter int, residualNorm float64) bool
+
+	// Cancel, if non-nil, stops the solve once it is
+	// closed.
+	Cancel <-chan struct{}
+
+	history *[]float64
+
+	residualGap *residualGap
+
+	checkDefinite bool
+	checkFinite   bool
+	workspace     *Solver
+	coefficients  *cgCoefficients
+	changeTol     float64
+	energyTol     float64
+	energyDelay   int
+	directions    *[]linalg.Vector
+}
+
+// SolveWith solves the symmetric positive-definite
+// system t*x = b using the given options.
+//
+// If the solve stops before converging, for example
+// because MaxIter was reached, the best approximation
+// found so far is returned as the solution.
+//
+// The other CG entry points, such as SolvePrec,
+// SolveRelative and SolveContext, are shorthands for
+// particular options.
+//
+// SolveWith only runs CG.
+// The other solvers, such as SolveBiCGSTAB,
+// SolveGMRES, SolveMINRES, SolveCR, SolveCGNR,
+// SolveLSQR, SolveBlock and SolveChebyshev, are
+// not routed through SolveOptions and keep their
+// own prec, maxIter and cancelChan arguments.
+func SolveWith(t LinTran, b linalg.Vector, opts SolveOptions) SolveResult {
+	return solve(t, b, &opts)
+}
@@ -0,0 +1,47 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// orthoDropTol is the relative norm below which a
+// vector is treated as linearly dependent on the ones
+// before it.
+const orthoDropTol = 1e-10
+
+// Orthonormalize returns an orthonormal basis for the
+// span of vecs, built with modified Gram-Schmidt.
+//
+// The vectors are processed in order, and a vector
+// whose norm drops below a small fraction of its
+// original norm after projection is treated as
+// linearly dependent and dropped.
+// Thus, the result may have fewer vectors than vecs.
+//
+// Each vector is projected twice, which keeps the
+// result orthogonal to working precision even when
+// the inputs are nearly dependent.
+// The input vectors are not modified.
+//
+// This panics if the vectors differ in length.
+func Orthonormalize(vecs []linalg.Vector) []linalg.Vector {
+	var basis []linalg.Vector
+	for _, v := range vecs {
+		if len(v) != len(vecs[0]) {
+			panic("dimension mismatch")
+		}
+		vec := v.Copy()
+		projectOut(vec, basis)
+		projectOut(vec, basis)
+		if mag := vec.Mag(); mag > orthoDropTol*v.Mag() && mag != 0 {
+			basis = append(basis, vec.Scale(1/mag))
+		}
+	}
+	return basis
+}
+
+// projectOut removes the components of v along each
+// of the orthonormal vectors in basis, one at a time.
+func projectOut(v linalg.Vector, basis []linalg.Vector) {
+	for _, q := range basis {
+		v.AddScaled(q, -q.Dot(v))
+	}
+}
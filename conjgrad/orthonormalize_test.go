@@ -0,0 +1,60 @@
+package conjgrad
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestOrthonormalize(t *testing.T) {
+	// Classical Gram-Schmidt loses orthogonality on these
+	// nearly dependent vectors.
+	eps := 1e-8
+	vecs := []linalg.Vector{
+		{1, eps, 0, 0},
+		{1, 0, eps, 0},
+		{1, 0, 0, eps},
+	}
+	basis := Orthonormalize(vecs)
+	if len(basis) != 3 {
+		t.Fatalf("expected 3 vectors but got %d", len(basis))
+	}
+	checkOrthonormal(t, basis)
+	for _, v := range vecs {
+		rest := v.Copy()
+		projectOut(rest, basis)
+		if rest.Mag() > 1e-12 {
+			t.Errorf("vector %v is not in the span of the basis", v)
+		}
+	}
+	if vecs[0][1] != eps {
+		t.Error("input was modified")
+	}
+
+	dependent := []linalg.Vector{
+		{1, 2, 3},
+		{0, 1, 1},
+		{1, 3, 4},
+		{2, 4, 6},
+	}
+	basis = Orthonormalize(dependent)
+	if len(basis) != 2 {
+		t.Fatalf("expected 2 vectors but got %d", len(basis))
+	}
+	checkOrthonormal(t, basis)
+}
+
+func checkOrthonormal(t *testing.T, basis []linalg.Vector) {
+	for i, u := range basis {
+		for j, v := range basis {
+			expected := 0.0
+			if i == j {
+				expected = 1
+			}
+			if actual := u.Dot(v); math.Abs(actual-expected) > 1e-12 {
+				t.Errorf("entry %d,%d of the Gram matrix is %g", i, j, actual)
+			}
+		}
+	}
+}
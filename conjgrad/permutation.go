@@ -0,0 +1,61 @@
+package conjgrad
+
+import (
+	"fmt"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// PermutationTran is a LinTran which reorders the
+// components of a vector.
+type PermutationTran struct {
+	perm []int
+}
+
+// NewPermutationTran creates a permutation for which
+// component i of the output is component perm[i] of
+// the input.
+//
+// This panics unless perm contains every index from 0
+// to len(perm)-1 exactly once.
+func NewPermutationTran(perm []int) *PermutationTran {
+	seen := make([]bool, len(perm))
+	for i, idx := range perm {
+		if idx < 0 || idx >= len(perm) {
+			panic(fmt.Sprintf("permutation index %d out of range at position %d", idx, i))
+		}
+		if seen[idx] {
+			panic(fmt.Sprintf("permutation index %d repeated at position %d", idx, i))
+		}
+		seen[idx] = true
+	}
+	return &PermutationTran{perm: append([]int{}, perm...)}
+}
+
+// Dim returns the number of components being
+// permuted.
+func (p *PermutationTran) Dim() int {
+	return len(p.perm)
+}
+
+// Apply returns the permuted vector.
+func (p *PermutationTran) Apply(v linalg.Vector) linalg.Vector {
+	if len(v) != len(p.perm) {
+		panic("dimension mismatch")
+	}
+	res := make(linalg.Vector, len(v))
+	for i, idx := range p.perm {
+		res[i] = v[idx]
+	}
+	return res
+}
+
+// Inverse returns the permutation which undoes p.
+// Since p is orthogonal, this is also its transpose.
+func (p *PermutationTran) Inverse() *PermutationTran {
+	inv := make([]int, len(p.perm))
+	for i, idx := range p.perm {
+		inv[idx] = i
+	}
+	return &PermutationTran{perm: inv}
+}
@@ -0,0 +1,86 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// SolvePipelined solves the symmetric positive-definite
+// system t*x = b using the pipelined CG method of
+// Ghysels and Vanroose.
+//
+// Standard CG needs the result of one inner product
+// before it can apply t, and of another right after.
+// Pipelined CG carries the extra vectors w = A*r,
+// s = A*p and z = A*s through the recurrence, so both
+// inner products of an iteration come from a single
+// reduction which does not depend on the next t.Apply.
+// Here, the reduction runs concurrently with the
+// t.Apply it overlaps.
+// In exact arithmetic the iterates match standard CG.
+//
+// The cost is three extra vector updates and three
+// extra vectors of storage per iteration.
+// The longer recurrences also lose accuracy faster,
+// so the vectors are recomputed from b-t*x every
+// residualUpdateFrequency iterations, at the cost of
+// three extra applications of t.
+//
+// The solve stops when no component of the residual
+// exceeds prec.
+func SolvePipelined(t LinTran, b linalg.Vector, prec float64) linalg.Vector {
+	return SolvePipelinedStoppable(t, b, prec, nil)
+}
+
+// SolvePipelinedStoppable is like SolvePipelined, but
+// it returns the current solution once cancelChan is
+// closed.
+func SolvePipelinedStoppable(t LinTran, b linalg.Vector, prec float64,
+	cancelChan <-chan struct{}) linalg.Vector {
+	n := t.Dim()
+	solution := make(linalg.Vector, n)
+	residual := b.Copy()
+	w := t.Apply(residual)
+	conjVec := make(linalg.Vector, n)
+	s := make(linalg.Vector, n)
+	z := make(linalg.Vector, n)
+
+	var lastGamma, lastAlpha float64
+	for iters := 0; residual.NormInf() > prec; iters++ {
+		if iters != 0 && iters%residualUpdateFrequency == 0 {
+			copy(residual, b)
+			residual.Sub(t.Apply(solution))
+			w = t.Apply(residual)
+			s = t.Apply(conjVec)
+			z = t.Apply(s)
+		}
+
+		applied := make(chan linalg.Vector, 1)
+		go func(w linalg.Vector) {
+			applied <- t.Apply(w)
+		}(w)
+		gamma := residual.Dot(residual)
+		delta := w.Dot(residual)
+		q := <-applied
+
+		var alpha, beta float64
+		if iters == 0 {
+			alpha = gamma / delta
+		} else {
+			beta = gamma / lastGamma
+			alpha = gamma / (delta - beta*gamma/lastAlpha)
+		}
+		if !(alpha > 0) {
+			break
+		}
+		z.Scale(beta).Add(q)
+		s.Scale(beta).Add(w)
+		conjVec.Scale(beta).Add(residual)
+		solution.AddScaled(conjVec, alpha)
+		residual.AddScaled(s, -alpha)
+		w.AddScaled(z, -alpha)
+		lastGamma, lastAlpha = gamma, alpha
+
+		if cancelled(cancelChan) {
+			break
+		}
+	}
+	return solution
+}
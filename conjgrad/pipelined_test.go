@@ -0,0 +1,24 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolvePipelined(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	checkSolution(t, SolvePipelined(lt, b, 1e-8), realSolution)
+
+	// Enough iterations to exercise the periodic
+	// recomputation of the recurrence vectors.
+	band := NewSymBand(60, 1)
+	for i := 0; i < 60; i++ {
+		band.SetBand(i, 0, 2.1)
+		if i+1 < 60 {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	expected := linalg.RandVector(60)
+	checkSolution(t, SolvePipelined(band, band.Apply(expected), 1e-10), expected)
+}
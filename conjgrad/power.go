@@ -0,0 +1,67 @@
+package conjgrad
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// SpectralNorm estimates the largest eigenvalue
+// magnitude of a symmetric operator t, which is also
+// its spectral norm, using iters steps of power
+// iteration from a random unit vector.
+//
+// Power iteration converges slowly when the two
+// largest eigenvalue magnitudes are close, in which
+// case the estimate may be too small.
+func SpectralNorm(t LinTran, iters int) float64 {
+	return SpectralNormRand(t, iters, nil)
+}
+
+// SpectralNormRand is like SpectralNorm, but it draws
+// the starting vector from gen for reproducibility.
+// If gen is nil, the global source is used.
+func SpectralNormRand(t LinTran, iters int, gen *rand.Rand) float64 {
+	var vec linalg.Vector
+	if gen != nil {
+		vec = randomUnitVector(gen, t.Dim())
+	} else {
+		vec = linalg.RandVector(t.Dim())
+	}
+	return SpectralNormFrom(t, vec, iters)
+}
+
+// SpectralNormFrom is like SpectralNorm, but power
+// iteration starts from the given vector instead of a
+// random one.
+// A start close to the dominant eigenvector speeds up
+// convergence.
+//
+// The start is normalized automatically.
+// This panics if it has the wrong length or is zero.
+func SpectralNormFrom(t LinTran, start linalg.Vector, iters int) float64 {
+	checkStart(t, start)
+	vec := start.Unit()
+	applied := t.Apply(vec)
+	for i := 0; i < iters; i++ {
+		mag := applied.Mag()
+		if mag == 0 {
+			return 0
+		}
+		vec = applied.Scale(1 / mag)
+		applied = t.Apply(vec)
+	}
+	return math.Abs(vec.Dot(applied))
+}
+
+// checkStart panics if start is not a valid starting
+// vector for an iteration on t.
+func checkStart(t LinTran, start linalg.Vector) {
+	if len(start) != t.Dim() {
+		panic("dimension mismatch")
+	}
+	if allZero(start) {
+		panic("starting vector must be non-zero")
+	}
+}
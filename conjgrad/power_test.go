@@ -0,0 +1,34 @@
+package conjgrad
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSpectralNorm(t *testing.T) {
+	diag := Diagonal(linalg.Vector{1, -7, 3, 2})
+	for _, gen := range []*rand.Rand{nil, rand.New(rand.NewSource(1))} {
+		norm := SpectralNormRand(diag, 200, gen)
+		if math.Abs(norm-7) > 1e-6 {
+			t.Errorf("expected 7 but got %f", norm)
+		}
+	}
+	if norm := SpectralNorm(diag, 200); math.Abs(norm-7) > 1e-6 {
+		t.Errorf("expected 7 but got %f", norm)
+	}
+}
+
+func TestSpectralNormFrom(t *testing.T) {
+	diag := Diagonal{1, -5, 2, 4.9}
+	// Starting at the dominant eigenvector needs no
+	// iterations at all.
+	if norm := SpectralNormFrom(diag, linalg.Vector{0, 3, 0, 0}, 0); norm != 5 {
+		t.Error("expected 5 but got", norm)
+	}
+	if norm := SpectralNormFrom(diag, linalg.Vector{1, 1, 1, 1}, 500); math.Abs(norm-5) > 1e-3 {
+		t.Error("expected 5 but got", norm)
+	}
+}
@@ -0,0 +1,138 @@
+package conjgrad
+
+import (
+	"fmt"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// A Preconditioner approximates the inverse of some
+// matrix M which is "close" to the operator being
+// inverted, but which is much cheaper to invert.
+//
+// For preconditioned conjugate gradients to work,
+// M must be symmetric positive-definite.
+type Preconditioner interface {
+	// ApplyInverse returns an approximation of
+	// M^-1*r.
+	ApplyInverse(r linalg.Vector) linalg.Vector
+}
+
+// linTranPreconditioner is a Preconditioner which
+// treats a LinTran as M^-1.
+type linTranPreconditioner struct {
+	t LinTran
+}
+
+func (l linTranPreconditioner) ApplyInverse(r linalg.Vector) linalg.Vector {
+	return l.t.Apply(r)
+}
+
+type identityPreconditioner struct{}
+
+func (_ identityPreconditioner) ApplyInverse(r linalg.Vector) linalg.Vector {
+	return r
+}
+
+// JacobiPreconditioner is a Preconditioner which
+// uses the diagonal of an operator as M.
+type JacobiPreconditioner struct {
+	diagonal linalg.Vector
+}
+
+// NewJacobiFromLinTran creates a JacobiPreconditioner
+// from the diagonal of t.
+// The diagonal is extracted by applying t to each
+// standard basis vector, so this requires t.Dim()
+// applications of t.
+//
+// This panics if any diagonal entry is zero.
+func NewJacobiFromLinTran(t LinTran) *JacobiPreconditioner {
+	diagonal := extractDiagonal(t)
+	for i, x := range diagonal {
+		if x == 0 {
+			panic(fmt.Sprintf("zero diagonal entry at index %d", i))
+		}
+	}
+	return &JacobiPreconditioner{diagonal: diagonal}
+}
+
+// NewVectorPreconditioner creates a Preconditioner
+// which divides each component of a vector by the
+// corresponding entry of scale.
+//
+// This is a Jacobi preconditioner whose diagonal is
+// given directly, which suits problems where good
+// scales for the variables are known in advance.
+// The scale vector is copied.
+//
+// This panics if any entry of scale is zero.
+func NewVectorPreconditioner(scale linalg.Vector) Preconditioner {
+	for i, x := range scale {
+		if x == 0 {
+			panic(fmt.Sprintf("zero scale entry at index %d", i))
+		}
+	}
+	return &JacobiPreconditioner{diagonal: scale.Copy()}
+}
+
+// ApplyInverse divides each component of r by the
+// corresponding diagonal entry.
+func (j *JacobiPreconditioner) ApplyInverse(r linalg.Vector) linalg.Vector {
+	if len(r) != len(j.diagonal) {
+		panic("dimension mismatch")
+	}
+	res := make(linalg.Vector, len(r))
+	for i, x := range r {
+		res[i] = x / j.diagonal[i]
+	}
+	return res
+}
+
+func extractDiagonal(t LinTran) linalg.Vector {
+	res := make(linalg.Vector, t.Dim())
+	basis := make(linalg.Vector, t.Dim())
+	for i := range res {
+		basis[i] = 1
+		res[i] = t.Apply(basis)[i]
+		basis[i] = 0
+	}
+	return res
+}
+
+// composedPreconditioner applies the inverses of its
+// preconditioners in a palindromic order.
+type composedPreconditioner []Preconditioner
+
+// ComposePreconditioners combines preconditioners
+// whose inverses are P1, P2, ..., Pk into one whose
+// inverse is the symmetric product
+//
+//	P1*P2*...*Pk*...*P2*P1
+//
+// PCG is only valid when M^-1 is symmetric positive-
+// definite, and a plain product P1*P2 generally is
+// not symmetric even when both factors are.
+// The palindromic product is, since it has the form
+// P1'*(P2*...*P2)*P1 with an SPD matrix in the middle.
+//
+// Every preconditioner but the last is applied twice
+// per ApplyInverse.
+// With no arguments, the result is the identity.
+func ComposePreconditioners(ps ...Preconditioner) Preconditioner {
+	return composedPreconditioner(append([]Preconditioner{}, ps...))
+}
+
+func (c composedPreconditioner) ApplyInverse(r linalg.Vector) linalg.Vector {
+	if len(c) == 0 {
+		return r.Copy()
+	}
+	res := r
+	for _, p := range c {
+		res = p.ApplyInverse(res)
+	}
+	for i := len(c) - 2; i >= 0; i-- {
+		res = c[i].ApplyInverse(res)
+	}
+	return res
+}
@@ -0,0 +1,113 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestJacobiPreconditioner(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	jacobi := NewJacobiFromLinTran(lt)
+	for i, x := range jacobi.diagonal {
+		if x != lt.M.Get(i, i) {
+			t.Errorf("diagonal entry %d should be %f but got %f", i, lt.M.Get(i, i), x)
+		}
+	}
+	checkSolution(t, SolvePreconditioned(lt, jacobi, b, 1e-8, nil), realSolution)
+}
+
+func TestVectorPreconditioner(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	scale := linalg.Vector{2, 5, 4, 1.5, 5}
+	m := NewVectorPreconditioner(scale)
+	scale[0] = 100
+	r := linalg.Vector{2, 10, 1, 3, -5}
+	expected := linalg.Vector{1, 2, 0.25, 2, -1}
+	if actual := m.ApplyInverse(r); !actual.ApproxEqual(expected, 1e-12) {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+	checkSolution(t, SolvePreconditioned(lt, m, b, 1e-8, nil), realSolution)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic for a zero scale")
+			}
+		}()
+		NewVectorPreconditioner(linalg.Vector{1, 0})
+	}()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic for a length mismatch")
+			}
+		}()
+		m.ApplyInverse(linalg.Vector{1, 2})
+	}()
+}
+
+func TestIncompleteCholesky(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	var rows, cols []int
+	var vals []float64
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 5; j++ {
+			rows = append(rows, i)
+			cols = append(cols, j)
+			vals = append(vals, lt.M.Get(i, j))
+		}
+	}
+	dense := NewSparseCSR(5, rows, cols, vals)
+
+	// With a dense pattern, IC(0) is an exact Cholesky
+	// factorization.
+	ic, err := NewIncompleteCholesky(dense)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, ic.ApplyInverse(b), realSolution)
+
+	tridiag := NewSparseCSR(4,
+		[]int{0, 0, 1, 1, 1, 2, 2, 2, 3, 3},
+		[]int{0, 1, 0, 1, 2, 1, 2, 3, 2, 3},
+		[]float64{2, -1, -1, 2, -1, -1, 2, -1, -1, 2})
+	ic, err = NewIncompleteCholesky(tridiag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := SolvePreconditioned(tridiag, ic, []float64{1, 0, 0, 1}, 1e-10, nil)
+	checkSolution(t, x, []float64{1, 1, 1, 1})
+
+	indefinite := NewSparseCSR(2, []int{0, 0, 1, 1}, []int{0, 1, 0, 1},
+		[]float64{1, 2, 2, 1})
+	if _, err := NewIncompleteCholesky(indefinite); err == nil {
+		t.Error("expected error for indefinite matrix")
+	}
+}
+
+func TestSSORPreconditioner(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	mat := &DenseMatrix{Rows: 5, Cols: 5, Data: lt.M.Data}
+	for _, omega := range []float64{0.5, 1, 1.5} {
+		ssor := NewSSORPreconditioner(mat, omega)
+		checkSolution(t, SolvePreconditioned(mat, ssor, b, 1e-8, nil), realSolution)
+	}
+}
+
+func TestComposePreconditioners(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	jacobi := NewJacobiFromLinTran(lt)
+	scaling := linTranPreconditioner{Diagonal{1, 2, 3, 4, 5}}
+	// A plain product of these two would not be
+	// symmetric.
+	sandwich := ComposePreconditioners(scaling, linTranPreconditioner{lt})
+	if err := CheckSymmetric(NewFuncTran(5, sandwich.ApplyInverse), 10, 1e-8); err != nil {
+		t.Error(err)
+	}
+
+	composed := ComposePreconditioners(scaling, jacobi)
+	checkSolution(t, SolvePreconditioned(lt, composed, b, 1e-8, nil), realSolution)
+	checkSolution(t, SolvePreconditioned(lt, ComposePreconditioners(), b, 1e-8, nil),
+		realSolution)
+}
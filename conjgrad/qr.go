@@ -0,0 +1,56 @@
+package conjgrad
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/unixpickle/num-analysis/linalg"
+	"github.com/unixpickle/num-analysis/linalg/qrdecomp"
+)
+
+// QRFactor is the thin QR factorization A = Q*R of an
+// MxN matrix A with M >= N, where Q is MxN with
+// orthonormal columns and R is NxN upper-triangular.
+type QRFactor struct {
+	q *DenseMatrix
+	r *DenseMatrix
+}
+
+// QR computes the QR factorization of a using
+// Householder reflections.
+//
+// An error is returned if a has more columns than
+// rows, or if it does not have full column rank.
+func QR(a *DenseMatrix) (*QRFactor, error) {
+	if a.Rows < a.Cols {
+		return nil, errors.New("matrix has more columns than rows")
+	}
+	q, r := qrdecomp.Householder(a.Matrix())
+	for i := 0; i < r.Rows; i++ {
+		if r.Get(i, i) == 0 {
+			return nil, fmt.Errorf("matrix is rank deficient (column %d)", i)
+		}
+	}
+	return &QRFactor{
+		q: &DenseMatrix{Rows: q.Rows, Cols: q.Cols, Data: q.Data},
+		r: &DenseMatrix{Rows: r.Rows, Cols: r.Cols, Data: r.Data},
+	}, nil
+}
+
+// R returns the upper-triangular factor.
+// The result should not be modified.
+func (q *QRFactor) R() *DenseMatrix {
+	return q.r
+}
+
+// SolveLeastSquares finds the x which minimizes the
+// 2-norm of A*x - b.
+func (q *QRFactor) SolveLeastSquares(b linalg.Vector) linalg.Vector {
+	res := q.q.ApplyTranspose(b)
+	n := q.r.Rows
+	for i := n - 1; i >= 0; i-- {
+		row := linalg.Vector(q.r.Data[i*n+i+1 : (i+1)*n])
+		res[i] = (res[i] - row.DotFast(res[i+1:])) / q.r.At(i, i)
+	}
+	return res
+}
@@ -0,0 +1,27 @@
+package conjgrad
+
+import "testing"
+
+func TestQR(t *testing.T) {
+	lt, b, realSolution := overdeterminedProblem()
+	dense := &DenseMatrix{Rows: lt.M.Rows, Cols: lt.M.Cols, Data: lt.M.Data}
+	factor, err := QR(dense)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, factor.SolveLeastSquares(b), realSolution)
+
+	r := factor.R()
+	for i := 0; i < r.Rows; i++ {
+		for j := 0; j < i; j++ {
+			if r.At(i, j) != 0 {
+				t.Errorf("R has non-zero entry at (%d, %d)", i, j)
+			}
+		}
+	}
+
+	wide := &DenseMatrix{Rows: 1, Cols: 2, Data: []float64{1, 2}}
+	if _, err := QR(wide); err == nil {
+		t.Error("expected error for wide matrix")
+	}
+}
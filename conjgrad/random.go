@@ -0,0 +1,60 @@
+package conjgrad
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// RandomVector creates a vector with entries sampled
+// from the standard normal using gen.
+func RandomVector(dim int, gen *rand.Rand) linalg.Vector {
+	res := make(linalg.Vector, dim)
+	for i := range res {
+		res[i] = gen.NormFloat64()
+	}
+	return res
+}
+
+// RandomSPD creates a random dim by dim symmetric
+// positive-definite matrix whose condition number is
+// condNumber, which must be at least 1.
+//
+// The matrix is Q*D*Q', where Q is a random orthogonal
+// matrix and the eigenvalues in D are spaced
+// geometrically from 1 to condNumber.
+// The same gen state always yields the same matrix.
+func RandomSPD(dim int, condNumber float64, gen *rand.Rand) *DenseMatrix {
+	if condNumber < 1 {
+		panic("condition number must be at least 1")
+	}
+	res := NewDenseMatrix(dim, dim)
+	for i := 0; i < dim; i++ {
+		exponent := 0.0
+		if dim > 1 {
+			exponent = float64(i) / float64(dim-1)
+		}
+		res.Set(i, i, math.Pow(condNumber, exponent))
+	}
+
+	// A product of dim random Householder reflections
+	// is a random orthogonal matrix.
+	for i := 0; i < dim; i++ {
+		reflectSym(res, randomUnitVector(gen, dim))
+	}
+	return res
+}
+
+// reflectSym replaces m with H*m*H, where H is the
+// Householder reflection I - 2*v*v' for a unit v.
+func reflectSym(m *DenseMatrix, v linalg.Vector) {
+	n := m.Rows
+	mv := m.Apply(v)
+	vmv := v.Dot(mv)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			m.Data[i*n+j] += 4*vmv*v[i]*v[j] - 2*(v[i]*mv[j]+mv[i]*v[j])
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package conjgrad
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRandomSPD(t *testing.T) {
+	matrix := RandomSPD(10, 1e3, rand.New(rand.NewSource(1)))
+	for i := 0; i < 10; i++ {
+		for j := 0; j < i; j++ {
+			if math.Abs(matrix.At(i, j)-matrix.At(j, i)) > 1e-12 {
+				t.Fatal("matrix is not symmetric")
+			}
+		}
+	}
+	values, _ := denseSymEigen(matrix)
+	if math.Abs(values[0]-1) > 1e-8 || math.Abs(values[9]-1e3) > 1e-8 {
+		t.Error("unexpected extreme eigenvalues", values[0], values[9])
+	}
+
+	other := RandomSPD(10, 1e3, rand.New(rand.NewSource(1)))
+	for i, x := range other.Data {
+		if x != matrix.Data[i] {
+			t.Fatal("same seed gave different matrices")
+		}
+	}
+
+	v := RandomVector(5, rand.New(rand.NewSource(2)))
+	checkSolution(t, RandomVector(5, rand.New(rand.NewSource(2))), v)
+}
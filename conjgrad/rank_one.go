@@ -0,0 +1,125 @@
+package conjgrad
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// rankOneTolerance is the relative size below which
+// the Sherman-Morrison denominator is considered to be
+// zero.
+const rankOneTolerance = 1e-12
+
+// SolveRankOneUpdate solves (A + u*u')*x = b using the
+// Sherman-Morrison formula, given a function baseSolve
+// which solves systems with A.
+//
+// The baseSolve function is called exactly twice, once
+// with b and once with u.
+//
+// The formula requires 1 + u'*inv(A)*u to be non-zero.
+// If it is zero relative to the size of its terms, the
+// updated matrix is (nearly) singular and an error is
+// returned.
+func SolveRankOneUpdate(baseSolve func(linalg.Vector) linalg.Vector,
+	u, b linalg.Vector) (linalg.Vector, error) {
+	if len(u) != len(b) {
+		panic("dimension mismatch")
+	}
+	y := baseSolve(b)
+	z := baseSolve(u)
+	uz := u.Dot(z)
+	denom := 1 + uz
+	if math.Abs(denom) <= rankOneTolerance*(1+math.Abs(uz)) {
+		return nil, fmt.Errorf("rank-one update is singular (1+u'*inv(A)*u = %g)", denom)
+	}
+	return y.Copy().AddScaled(z, -u.Dot(y)/denom), nil
+}
+
+// LowRankUpdateTran creates a LinTran which represents
+// A + U*U', where the columns of U are the vectors in
+// u, without forming the dense update.
+// Each Apply costs one a.Apply and len(u) inner
+// products.
+//
+// Every vector in u must have dimension a.Dim().
+// If a is symmetric positive-definite, so is the
+// result, and it can be solved with CG.
+func LowRankUpdateTran(a LinTran, u []linalg.Vector) LinTran {
+	vecs := make([]linalg.Vector, len(u))
+	for i, vec := range u {
+		if len(vec) != a.Dim() {
+			panic(fmt.Sprintf("dimension mismatch: update vector %d has dimension %d, not %d",
+				i, len(vec), a.Dim()))
+		}
+		vecs[i] = vec.Copy()
+	}
+	return lowRankUpdateTran{a: a, u: vecs}
+}
+
+type lowRankUpdateTran struct {
+	a LinTran
+	u []linalg.Vector
+}
+
+func (l lowRankUpdateTran) Dim() int {
+	return l.a.Dim()
+}
+
+func (l lowRankUpdateTran) Apply(x linalg.Vector) linalg.Vector {
+	weights := dotAll(l.u, x)
+	res := l.a.Apply(x).Copy()
+	for i, vec := range l.u {
+		res.AddScaled(vec, weights[i])
+	}
+	return res
+}
+
+// SolveWoodbury solves (A + U*U')*x = b using the
+// Woodbury matrix identity, where the columns of U are
+// the vectors in u and baseSolve solves systems with A.
+//
+// The baseSolve function is called once with b and
+// once with each vector in u.
+// The results are combined by solving the small
+// capacitance system (I + U'*inv(A)*U) with a dense
+// Cholesky factorization.
+//
+// If A is symmetric positive-definite, so is the
+// capacitance matrix.
+// An error is returned if its factorization fails.
+func SolveWoodbury(baseSolve func(linalg.Vector) linalg.Vector, u []linalg.Vector,
+	b linalg.Vector) (linalg.Vector, error) {
+	for _, vec := range u {
+		if len(vec) != len(b) {
+			panic("dimension mismatch")
+		}
+	}
+	y := baseSolve(b)
+	if len(u) == 0 {
+		return y, nil
+	}
+	solved := make([]linalg.Vector, len(u))
+	for i, vec := range u {
+		solved[i] = baseSolve(vec)
+	}
+	capacitance := NewDenseMatrix(len(u), len(u))
+	for i := range u {
+		for j := 0; j <= i; j++ {
+			capacitance.SetSym(i, j, u[i].Dot(solved[j]))
+		}
+		capacitance.Set(i, i, capacitance.At(i, i)+1)
+	}
+	factor, err := Cholesky(capacitance)
+	if err != nil {
+		return nil, fmt.Errorf("capacitance matrix: %s", err)
+	}
+	weights := factor.Solve(dotAll(u, y))
+	res := y.Copy()
+	for i, z := range solved {
+		res.AddScaled(z, -weights[i])
+	}
+	return res, nil
+}
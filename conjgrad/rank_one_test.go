@@ -0,0 +1,99 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveRankOneUpdate(t *testing.T) {
+	lt, _, _ := testProblem()
+	u := linalg.Vector{1, -1, 0.5, 2, 0}
+	expected := linalg.Vector{1, 2, 3, 4, 5}
+	b := lt.Apply(expected).Add(u.Copy().Scale(u.Dot(expected)))
+
+	var calls int
+	baseSolve := func(v linalg.Vector) linalg.Vector {
+		calls++
+		return SolvePrec(lt, nil, v, 1e-12)
+	}
+	solution, err := SolveRankOneUpdate(baseSolve, u, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 base solves but got %d", calls)
+	}
+	checkSolution(t, solution, expected)
+
+	identity := func(v linalg.Vector) linalg.Vector {
+		return v.Copy()
+	}
+	_, err = SolveRankOneUpdate(identity, linalg.Vector{1, 0}, linalg.Vector{1, 1})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// Updating -I by e1*e1' zeroes the first diagonal
+	// entry, so the result is singular.
+	negIdentity := func(v linalg.Vector) linalg.Vector {
+		return v.Copy().Scale(-1)
+	}
+	_, err = SolveRankOneUpdate(negIdentity, linalg.Vector{1, 0}, linalg.Vector{1, 1})
+	if err == nil {
+		t.Error("expected error for a singular update")
+	}
+}
+
+func TestLowRankUpdateTran(t *testing.T) {
+	lt, _, _ := testProblem()
+	u := []linalg.Vector{{1, -1, 0.5, 2, 0}, {0, 3, 1, -1, 1}}
+	updated := LowRankUpdateTran(lt, u)
+	if updated.Dim() != 5 {
+		t.Fatal("unexpected dimension", updated.Dim())
+	}
+	x := linalg.Vector{0.5, 1, -2, 3, 1}
+	expected := lt.Apply(x)
+	for _, vec := range u {
+		expected.Add(vec.Copy().Scale(vec.Dot(x)))
+	}
+	checkSolution(t, updated.Apply(x), expected)
+
+	// An operator which returns its argument must not
+	// corrupt the input or the inner products.
+	identity := NewFuncTran(2, func(v linalg.Vector) linalg.Vector { return v })
+	v := linalg.Vector{1, 2}
+	checkSolution(t, LowRankUpdateTran(identity, []linalg.Vector{{1, 0}}).Apply(v),
+		linalg.Vector{2, 2})
+	checkSolution(t, v, linalg.Vector{1, 2})
+}
+
+func TestSolveWoodbury(t *testing.T) {
+	lt, _, _ := testProblem()
+	u := []linalg.Vector{{1, -1, 0.5, 2, 0}, {0, 3, 1, -1, 1}}
+	expected := linalg.Vector{1, 2, 3, 4, 5}
+	b := LowRankUpdateTran(lt, u).Apply(expected)
+
+	var calls int
+	baseSolve := func(v linalg.Vector) linalg.Vector {
+		calls++
+		return SolvePrec(lt, nil, v, 1e-12)
+	}
+	solution, err := SolveWoodbury(baseSolve, u, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 base solves but got %d", calls)
+	}
+	checkSolution(t, solution, expected)
+
+	// With A = -I, the capacitance matrix I - U'*U is
+	// not positive-definite for a long enough u.
+	negIdentity := func(v linalg.Vector) linalg.Vector {
+		return v.Copy().Scale(-1)
+	}
+	_, err = SolveWoodbury(negIdentity, []linalg.Vector{{2, 0}}, linalg.Vector{1, 1})
+	if err == nil {
+		t.Error("expected error for indefinite capacitance matrix")
+	}
+}
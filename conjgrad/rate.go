@@ -0,0 +1,47 @@
+package conjgrad
+
+import "math"
+
+// EstimateConvergenceRate estimates the asymptotic
+// factor by which the residual shrinks per iteration,
+// given a residual history like the one returned by
+// SolveWithHistory.
+// For example, 0.7 means that the residual shrinks by
+// 30% per iteration.
+//
+// The first half of the history is treated as a
+// transient and ignored.
+// A line is fit to the logarithms of the remaining
+// residuals by least squares, so the estimate is not
+// thrown off by a non-monotone history.
+// Non-positive and non-finite residuals are skipped.
+//
+// If fewer than two residuals can be used, NaN is
+// returned.
+func EstimateConvergenceRate(history []float64) float64 {
+	var xs, ys []float64
+	for i := len(history) / 2; i < len(history); i++ {
+		h := history[i]
+		if h > 0 && !math.IsInf(h, 0) {
+			xs = append(xs, float64(i))
+			ys = append(ys, math.Log(h))
+		}
+	}
+	if len(xs) < 2 {
+		return math.NaN()
+	}
+
+	var meanX, meanY float64
+	for i, x := range xs {
+		meanX += x
+		meanY += ys[i]
+	}
+	meanX /= float64(len(xs))
+	meanY /= float64(len(xs))
+	var cov, varX float64
+	for i, x := range xs {
+		cov += (x - meanX) * (ys[i] - meanY)
+		varX += (x - meanX) * (x - meanX)
+	}
+	return math.Exp(cov / varX)
+}
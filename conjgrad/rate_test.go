@@ -0,0 +1,34 @@
+package conjgrad
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateConvergenceRate(t *testing.T) {
+	// A slow transient followed by zig-zagging decay
+	// at a rate of 0.5.
+	history := []float64{10, 9.9, 9.8, 9.7}
+	value := 1.0
+	for i := 0; i < 20; i++ {
+		zigzag := 1.0
+		if i%2 == 1 {
+			zigzag = 1.5
+		}
+		history = append(history, value*zigzag)
+		value *= 0.5
+	}
+	if rate := EstimateConvergenceRate(history); math.Abs(rate-0.5) > 0.02 {
+		t.Error("expected rate near 0.5 but got", rate)
+	}
+
+	if rate := EstimateConvergenceRate([]float64{1}); !math.IsNaN(rate) {
+		t.Error("expected NaN but got", rate)
+	}
+
+	lt, b, _ := testProblem()
+	_, history = SolveWithHistory(lt, b, 1e-8)
+	if rate := EstimateConvergenceRate(history); !(rate < 1) {
+		t.Error("expected rate below 1 but got", rate)
+	}
+}
@@ -0,0 +1,145 @@
+package conjgrad
+
+import (
+	"github.com/unixpickle/num-analysis/conjgrad/conjgrad32"
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+const (
+	// refineInnerReduction is the factor by which each
+	// single-precision inner solve tries to reduce the
+	// residual.
+	refineInnerReduction = 1e-4
+
+	// refineMaxSteps bounds the number of outer
+	// refinement steps.
+	refineMaxSteps = 50
+)
+
+// SolveRefined solves t*x = b using mixed-precision
+// iterative refinement.
+//
+// Each outer step computes r = b-t*x in double
+// precision, approximately solves t*d = r with
+// single-precision CG, and adds d to x.
+// This repeats until no component of the residual
+// exceeds prec.
+func SolveRefined(t LinTran, b linalg.Vector, prec float64) linalg.Vector {
+	return SolveRefinedDetailed(t, b, prec).Solution
+}
+
+// SolveRefinedStoppable is like SolveRefined, but it
+// returns the current solution once cancelChan is
+// closed.
+// The channel is checked before each outer step.
+func SolveRefinedStoppable(t LinTran, b linalg.Vector, prec float64,
+	cancelChan <-chan struct{}) linalg.Vector {
+	return refinedSolve(t, b, prec, cancelChan).Solution
+}
+
+// SolveRefinedDetailed is like SolveRefined, but it
+// returns a SolveResult whose Iterations field is the
+// number of outer refinement steps.
+//
+// The solve gives up if an outer step fails to reduce
+// the residual, in which case Converged is false.
+func SolveRefinedDetailed(t LinTran, b linalg.Vector, prec float64) SolveResult {
+	return refinedSolve(t, b, prec, nil)
+}
+
+func refinedSolve(t LinTran, b linalg.Vector, prec float64,
+	cancelChan <-chan struct{}) SolveResult {
+	solution := make(linalg.Vector, t.Dim())
+	residual := b.Copy()
+	resNorm := residual.NormInf()
+	t32 := &refineTran32{t: t}
+
+	var steps int
+	for resNorm > prec && !stopIteration(steps, refineMaxSteps, cancelChan) {
+		// Scale the residual to unit size so that it
+		// does not underflow in single precision.
+		scale := resNorm
+		r32 := make(conjgrad32.Vector32, len(residual))
+		for i, x := range residual {
+			r32[i] = float32(x / scale)
+		}
+
+		t32.applies = 0
+		t32.limit = 2 * t.Dim()
+		t32.cancel = make(chan struct{})
+		d32 := conjgrad32.SolveStoppable32(t32, nil, r32, refineInnerReduction, t32.cancel)
+
+		next := solution.Copy()
+		for i, x := range d32 {
+			next[i] += float64(x) * scale
+		}
+		nextResidual := b.Copy().Sub(t.Apply(next))
+		nextNorm := nextResidual.NormInf()
+		if nextNorm >= resNorm {
+			break
+		}
+		solution, residual, resNorm = next, nextResidual, nextNorm
+		steps++
+	}
+
+	return SolveResult{
+		Solution:      solution,
+		Iterations:    steps,
+		FinalResidual: resNorm,
+		Converged:     resNorm <= prec,
+	}
+}
+
+// refineTran32 applies a double-precision LinTran to
+// single-precision vectors.
+//
+// It closes cancel after limit applications, since
+// single-precision CG may never reach its tolerance.
+type refineTran32 struct {
+	t       LinTran
+	applies int
+	limit   int
+	cancel  chan struct{}
+}
+
+func (r *refineTran32) Dim() int {
+	return r.t.Dim()
+}
+
+func (r *refineTran32) Apply(v conjgrad32.Vector32) conjgrad32.Vector32 {
+	r.applies++
+	if r.applies == r.limit {
+		close(r.cancel)
+	}
+	v64 := make(linalg.Vector, len(v))
+	for i, x := range v {
+		v64[i] = float64(x)
+	}
+	res64 := r.t.Apply(v64)
+	res := make(conjgrad32.Vector32, len(res64))
+	for i, x := range res64 {
+		res[i] = float32(x)
+	}
+	return res
+}
+
+// RichardsonExtrapolate combines two approximations
+// of the same solution into a more accurate one.
+//
+// It assumes the error in fine is smaller than the
+// error in coarse by a factor of ratio, with both
+// errors pointing in the same direction, and returns
+//
+//	fine + (fine-coarse)/(ratio-1)
+//
+// which cancels that error exactly.
+// This panics if the lengths differ or if ratio is 1.
+func RichardsonExtrapolate(coarse, fine linalg.Vector, ratio float64) linalg.Vector {
+	if len(coarse) != len(fine) {
+		panic("dimension mismatch")
+	}
+	if ratio == 1 {
+		panic("ratio must not be 1")
+	}
+	return fine.Copy().AddScaled(fine.Copy().Sub(coarse), 1/(ratio-1))
+}
@@ -0,0 +1,44 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveRefined(t *testing.T) {
+	dim := 30
+	band := NewSymBand(dim, 1)
+	for i := 0; i < dim; i++ {
+		band.SetBand(i, 0, 2)
+		if i+1 < dim {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	expected := make(linalg.Vector, dim)
+	for i := range expected {
+		expected[i] = float64(i%7) - 3.1
+	}
+	b := band.Apply(expected)
+
+	res := SolveRefinedDetailed(band, b, 1e-10)
+	if !res.Converged {
+		t.Fatalf("did not converge (residual %g)", res.FinalResidual)
+	}
+	if res.Iterations < 2 {
+		t.Errorf("expected multiple refinement steps but got %d", res.Iterations)
+	}
+	checkSolution(t, res.Solution, expected)
+	checkSolution(t, SolveRefined(band, b, 1e-10), expected)
+}
+
+func TestRichardsonExtrapolate(t *testing.T) {
+	exact := linalg.Vector{1, -2, 3}
+	errVec := linalg.Vector{0.5, 0.25, -1}
+	coarse := exact.Copy().AddScaled(errVec, 1)
+	fine := exact.Copy().AddScaled(errVec, 0.25)
+	actual := RichardsonExtrapolate(coarse, fine, 4)
+	if !actual.ApproxEqual(exact, 1e-12) {
+		t.Errorf("expected %v but got %v", exact, actual)
+	}
+}
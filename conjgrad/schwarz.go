@@ -0,0 +1,146 @@
+package conjgrad
+
+import (
+	"fmt"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// AdditiveSchwarz is a domain-decomposition
+// Preconditioner.
+// Its ApplyInverse solves a small problem on each
+// subdomain and sums the corrections.
+type AdditiveSchwarz struct {
+	dim        int
+	subdomains [][]int
+	factors    []*CholeskyFactor
+}
+
+// NewAdditiveSchwarz creates an additive Schwarz
+// preconditioner for a symmetric positive-definite t.
+//
+// Each subdomain is a set of indices, and together the
+// subdomains must cover every index.
+// Before the local problems are built, each subdomain
+// is grown by overlap layers of neighboring indices,
+// where i and j are neighbors if t has a non-zero
+// entry at (i, j).
+// The local matrix for each subdomain is extracted and
+// factored with a dense Cholesky decomposition.
+//
+// Corrections on overlapping indices are summed rather
+// than averaged.
+// Summing gives M^-1 = sum(R'*A_i^-1*R), which is
+// symmetric positive-definite as PCG requires, while
+// averaging would not be symmetric.
+//
+// Entries are read with t's row iteration when it is a
+// *DenseMatrix, *SparseCSR, or *SymBand.
+// Otherwise, t is applied to a standard basis vector
+// for every index in every grown subdomain.
+//
+// This panics if an index is not covered, or if a
+// local matrix is not positive-definite.
+func NewAdditiveSchwarz(t LinTran, subdomains [][]int, overlap int) *AdditiveSchwarz {
+	row := schwarzRowFunc(t)
+	covered := make([]bool, t.Dim())
+	res := &AdditiveSchwarz{dim: t.Dim()}
+	for _, sub := range subdomains {
+		indices := growSubdomain(row, sub, overlap)
+		for _, idx := range sub {
+			covered[idx] = true
+		}
+		position := map[int]int{}
+		for i, idx := range indices {
+			position[idx] = i
+		}
+		local := NewDenseMatrix(len(indices), len(indices))
+		for i, idx := range indices {
+			row(idx, func(col int, val float64) {
+				if j, ok := position[col]; ok {
+					local.Set(i, j, val)
+				}
+			})
+		}
+		factor, err := Cholesky(local)
+		if err != nil {
+			panic("local matrix is not positive-definite: " + err.Error())
+		}
+		res.subdomains = append(res.subdomains, indices)
+		res.factors = append(res.factors, factor)
+	}
+	for i, c := range covered {
+		if !c {
+			panic(fmt.Sprintf("index %d is not in any subdomain", i))
+		}
+	}
+	return res
+}
+
+// ApplyInverse restricts r to every subdomain, solves
+// the local problems, and sums the results.
+func (a *AdditiveSchwarz) ApplyInverse(r linalg.Vector) linalg.Vector {
+	if len(r) != a.dim {
+		panic("dimension mismatch")
+	}
+	res := make(linalg.Vector, a.dim)
+	for i, indices := range a.subdomains {
+		local := make(linalg.Vector, len(indices))
+		for j, idx := range indices {
+			local[j] = r[idx]
+		}
+		for j, x := range a.factors[i].Solve(local) {
+			res[indices[j]] += x
+		}
+	}
+	return res
+}
+
+// schwarzRowFunc returns a function which iterates
+// over the non-zero entries in a row of t.
+func schwarzRowFunc(t LinTran) func(row int, f func(col int, val float64)) {
+	if rt, ok := t.(rowTran); ok {
+		return rt.iterRow
+	}
+	basis := make(linalg.Vector, t.Dim())
+	return func(row int, f func(col int, val float64)) {
+		// Since t is symmetric, row i is column i.
+		basis[row] = 1
+		column := t.Apply(basis)
+		basis[row] = 0
+		for col, val := range column {
+			if val != 0 {
+				f(col, val)
+			}
+		}
+	}
+}
+
+// growSubdomain adds overlap layers of neighbors to
+// the indices in sub, returning them without
+// duplicates.
+func growSubdomain(row func(int, func(int, float64)), sub []int, overlap int) []int {
+	seen := map[int]bool{}
+	var indices []int
+	for _, idx := range sub {
+		if !seen[idx] {
+			seen[idx] = true
+			indices = append(indices, idx)
+		}
+	}
+	frontier := indices
+	for layer := 0; layer < overlap; layer++ {
+		var next []int
+		for _, idx := range frontier {
+			row(idx, func(col int, val float64) {
+				if !seen[col] {
+					seen[col] = true
+					next = append(next, col)
+				}
+			})
+		}
+		indices = append(indices, next...)
+		frontier = next
+	}
+	return indices
+}
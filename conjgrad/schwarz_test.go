@@ -0,0 +1,44 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestAdditiveSchwarz(t *testing.T) {
+	const dim = 40
+	band := NewSymBand(dim, 1)
+	for i := 0; i < dim; i++ {
+		band.SetBand(i, 0, 2)
+		if i+1 < dim {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	var subdomains [][]int
+	for start := 0; start < dim; start += 10 {
+		var sub []int
+		for i := start; i < start+10; i++ {
+			sub = append(sub, i)
+		}
+		subdomains = append(subdomains, sub)
+	}
+	expected := linalg.RandVector(dim)
+	b := band.Apply(expected)
+
+	plain := SolveWith(band, b, SolveOptions{Tolerance: 1e-10})
+	for _, overlap := range []int{0, 2} {
+		m := NewAdditiveSchwarz(band, subdomains, overlap)
+		res := solve(band, b, &SolveOptions{Tolerance: 1e-10, Preconditioner: m})
+		checkSolution(t, res.Solution, expected)
+		if res.Iterations >= plain.Iterations {
+			t.Errorf("overlap %d: took %d iterations but plain CG took %d",
+				overlap, res.Iterations, plain.Iterations)
+		}
+	}
+
+	// Operators without row access are probed instead.
+	funcTran := NewFuncTran(dim, band.Apply)
+	m := NewAdditiveSchwarz(funcTran, subdomains, 1)
+	checkSolution(t, SolvePreconditioned(funcTran, m, b, 1e-10, nil), expected)
+}
@@ -1,14 +1,90 @@
 package conjgrad
 
-import "github.com/unixpickle/num-analysis/linalg"
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
 
 const residualUpdateFrequency = 20
 
+// residualFloorImprovement is the factor by which the
+// true residual must shrink between refreshes when
+// SolveOptions.DetectResidualFloor is set.
+const residualFloorImprovement = 0.99
+
+// timeoutCheckInterval is the number of iterations
+// between clock checks in SolveTimeout.
+const timeoutCheckInterval = 8
+
+// defaultRelativeTolerance is the tolerance, relative
+// to b, used by Solve when no precision is given.
+const defaultRelativeTolerance = 1e-12
+
+// defaultEnergyDelay is the delay used by
+// SolveEnergyTol.
+const defaultEnergyDelay = 4
+
+// SolveResult stores the outcome of a solve.
+type SolveResult struct {
+	// Solution is the approximate solution x.
+	Solution linalg.Vector
+
+	// Iterations is the number of iterations
+	// that were performed.
+	Iterations int
+
+	// FinalResidual is the largest absolute value
+	// of any component of b-Ax.
+	FinalResidual float64
+
+	// Converged is true if the solve stopped because
+	// the residual dropped below the requested bound.
+	// It is false if the solve was cancelled or broke
+	// down before reaching the bound.
+	Converged bool
+
+	// Stagnated is true if the solve stopped early
+	// because the residual stopped improving, as
+	// configured by SolveOptions.StagnationWindow.
+	Stagnated bool
+
+	// FloorReached is true if the solve stopped early
+	// because the true residual stopped improving, as
+	// configured by SolveOptions.DetectResidualFloor.
+	FloorReached bool
+
+	err error
+}
+
+// NotDefiniteError is returned when a solver finds
+// evidence that an operator is not positive-definite.
+type NotDefiniteError struct {
+	// Iteration is the iteration at which the
+	// problem was detected.
+	Iteration int
+
+	// Curvature is the value of p'*A*p for the
+	// search direction p, which should have been
+	// positive.
+	Curvature float64
+}
+
+func (n *NotDefiniteError) Error() string {
+	return fmt.Sprintf("operator is not positive-definite: p'Ap = %g at iteration %d",
+		n.Curvature, n.Iteration)
+}
+
 // SolveStoppable solves a system of linear equations
 // t*x = b for x, where t is a symmetric positive-definite
 // linear operator.
 //
 // If precond is nil, then no preconditioning is used.
+// Otherwise, precond should approximate the inverse
+// of t.
 //
 // The prec argument specifies a bound on the
 // residual error of the solution. If the largest
@@ -21,50 +97,280 @@ const residualUpdateFrequency = 20
 // solution is returned.
 func SolveStoppable(t, precond LinTran, b linalg.Vector, prec float64,
 	cancelChan <-chan struct{}) linalg.Vector {
-	if precond == nil {
-		precond = identity{}
+	var m Preconditioner
+	if precond != nil {
+		m = linTranPreconditioner{precond}
 	}
+	return SolvePreconditioned(t, m, b, prec, cancelChan)
+}
 
-	var conjVec linalg.Vector
-	var residual linalg.Vector
-	var solution linalg.Vector
+// SolvePreconditioned is like SolveStoppable, but it
+// takes a Preconditioner rather than a LinTran which
+// approximates the inverse of t.
+//
+// If m is nil, then no preconditioning is used.
+func SolvePreconditioned(t LinTran, m Preconditioner, b linalg.Vector, prec float64,
+	cancelChan <-chan struct{}) linalg.Vector {
+	return solve(t, b, &SolveOptions{
+		Tolerance:      prec,
+		Preconditioner: m,
+		Cancel:         cancelChan,
+	}).Solution
+}
 
-	var lastResidualDot float64
+// Solve solves t*x = b without a preconditioner,
+// stopping once the largest element of (Ax-b) has an
+// absolute value no greater than prec.
+//
+// If prec is 0, the solve is meant to be as accurate
+// as possible.
+// Since an exactly zero residual is usually out of
+// reach in floating point, a prec of 0 is treated as
+// defaultRelativeTolerance (1e-12) times the largest
+// absolute value of any component of b.
+func Solve(t LinTran, b linalg.Vector, prec float64) linalg.Vector {
+	if prec == 0 {
+		return SolveRelative(t, b, defaultRelativeTolerance, nil)
+	}
+	return SolvePrec(t, nil, b, prec)
+}
 
-	residual = b.Copy()
-	solution = make(linalg.Vector, t.Dim())
+// SolveDetailed is like SolvePrec without a
+// preconditioner, but it returns diagnostic
+// information along with the solution.
+func SolveDetailed(t LinTran, b linalg.Vector, prec float64) SolveResult {
+	return solve(t, b, &SolveOptions{Tolerance: prec})
+}
 
-	for i := 0; residual.MaxAbs() > prec; i++ {
-		z := precond.Apply(residual)
-		if i == 0 {
-			conjVec = z.Copy()
-			lastResidualDot = z.Dot(residual)
-		} else {
-			residualDot := z.Dot(residual)
-			projAmount := -residualDot / lastResidualDot
-			lastResidualDot = residualDot
-			conjVec = z.Copy().Add(conjVec.Scale(-projAmount))
-		}
-		if allZero(conjVec) {
-			break
-		}
-		optimalDistance := z.Dot(residual) / conjVec.Dot(t.Apply(conjVec))
+// SolveWithResidualGap is like SolvePrec without a
+// preconditioner, but it reports how far the cheap
+// recurrence for the residual drifts from the true
+// residual b-Ax.
+//
+// Whenever the true residual is recomputed (every
+// residualUpdateFrequency iterations), the infinity
+// norm of the recurrence residual it replaces is
+// appended to recurrence, and the norm of the true
+// residual is appended to actual.
+// A large gap between the two is a sign of lost
+// orthogonality; see SolveOptions.ResidualRefreshInterval.
+func SolveWithResidualGap(t LinTran, b linalg.Vector,
+	prec float64) (solution linalg.Vector, recurrence, actual []float64) {
+	var gap residualGap
+	res := solve(t, b, &SolveOptions{Tolerance: prec, residualGap: &gap})
+	return res.Solution, gap.recurrence, gap.actual
+}
 
-		solution.Add(conjVec.Copy().Scale(optimalDistance))
-		if i != 0 && (i%residualUpdateFrequency) == 0 {
-			residual = t.Apply(solution).Scale(-1).Add(b)
-		} else {
-			residual.Add(t.Apply(conjVec).Scale(-optimalDistance))
-		}
+// residualGap records the residual norms for
+// SolveWithResidualGap.
+type residualGap struct {
+	recurrence []float64
+	actual     []float64
+}
 
-		select {
-		case <-cancelChan:
-			return solution
-		default:
-		}
+// SolveCountingIters is like SolvePrec without a
+// preconditioner, but it also returns the number of
+// iterations which were performed.
+// If b is already within prec of zero, no iterations
+// are needed and 0 is returned.
+func SolveCountingIters(t LinTran, b linalg.Vector, prec float64) (linalg.Vector, int) {
+	res := solve(t, b, &SolveOptions{Tolerance: prec})
+	return res.Solution, res.Iterations
+}
+
+// SolveRelative is like SolveStoppable without a
+// preconditioner, but the bound on the residual is
+// relative to b.
+// The solve stops once the largest absolute value
+// of any component of (Ax-b) is at most relTol times
+// the largest absolute value of any component of b.
+//
+// If b is zero, then the zero vector is returned
+// right away.
+func SolveRelative(t LinTran, b linalg.Vector, relTol float64,
+	cancelChan <-chan struct{}) linalg.Vector {
+	return solve(t, b, &SolveOptions{
+		RelativeTolerance: relTol,
+		Cancel:            cancelChan,
+	}).Solution
+}
+
+// SolveContext is like SolveStoppable without a
+// preconditioner, but the solve is stopped when ctx
+// is done.
+//
+// If ctx is done before the solve converges, the
+// approximate solution is returned along with
+// ctx.Err().
+func SolveContext(ctx context.Context, t LinTran, b linalg.Vector,
+	prec float64) (linalg.Vector, error) {
+	res := solve(t, b, &SolveOptions{
+		Tolerance: prec,
+		Cancel:    ctx.Done(),
+	})
+	if !res.Converged {
+		return res.Solution, ctx.Err()
+	}
+	return res.Solution, nil
+}
+
+// SolveTimeout is like SolvePrec without a
+// preconditioner, but the solve is stopped once the
+// budget has elapsed.
+//
+// To keep the cost of reading the clock down, the
+// deadline is only checked every 8 iterations, so
+// the solve may run a few iterations past it.
+// The returned flag reports whether the solve
+// converged within the budget.
+func SolveTimeout(t LinTran, b linalg.Vector, prec float64,
+	budget time.Duration) (linalg.Vector, bool) {
+	deadline := time.Now().Add(budget)
+	res := solve(t, b, &SolveOptions{
+		Tolerance: prec,
+		Observe: func(iter int, residualNorm float64) bool {
+			return iter%timeoutCheckInterval != 0 || time.Now().Before(deadline)
+		},
+	})
+	return res.Solution, res.Converged
+}
+
+// SolveGuess is like SolvePrec without a
+// preconditioner, but the solve starts from an
+// initial guess x0 rather than from zero.
+//
+// The length of x0 must match t.Dim().
+func SolveGuess(t LinTran, b, x0 linalg.Vector, prec float64) linalg.Vector {
+	if len(x0) != t.Dim() {
+		panic("dimension mismatch")
+	}
+	return solve(t, b, &SolveOptions{Tolerance: prec, InitialGuess: x0}).Solution
+}
+
+// SolveObserved is like SolvePrec without a
+// preconditioner, but it calls observe after every
+// iteration.
+//
+// The observe function is passed the number of
+// iterations completed so far and the largest
+// absolute value of any component of the residual.
+// If observe returns false, the solve is stopped
+// and the current solution is returned.
+func SolveObserved(t LinTran, b linalg.Vector, prec float64,
+	observe func(iter int, residualNorm float64) bool) linalg.Vector {
+	return solve(t, b, &SolveOptions{Tolerance: prec, Observe: observe}).Solution
+}
+
+// SolveWithHistory is like SolvePrec without a
+// preconditioner, but it also returns the 2-norm
+// of the residual after each iteration.
+func SolveWithHistory(t LinTran, b linalg.Vector, prec float64) (linalg.Vector, []float64) {
+	var history []float64
+	solution := solve(t, b, &SolveOptions{Tolerance: prec, history: &history}).Solution
+	return solution, history
+}
+
+// SolveUntilStable solves t*x = b, stopping once the
+// 2-norm of a single update to x drops below
+// changeTol rather than waiting on the residual.
+//
+// This can stop prematurely if a search direction
+// happens to be nearly orthogonal to the remaining
+// error, since such a step is small even though x is
+// still far from the solution.
+func SolveUntilStable(t LinTran, b linalg.Vector, changeTol float64) linalg.Vector {
+	return solve(t, b, &SolveOptions{changeTol: changeTol}).Solution
+}
+
+// SolveEnergyTol solves t*x = b, stopping once an
+// estimate of the t-norm of the error x-x* drops below
+// energyTol.
+//
+// It is like SolveEnergyTolDelay with a delay of
+// defaultEnergyDelay.
+func SolveEnergyTol(t LinTran, b linalg.Vector, energyTol float64) linalg.Vector {
+	return SolveEnergyTolDelay(t, b, energyTol, defaultEnergyDelay)
+}
+
+// SolveEnergyTolDelay is like SolveEnergyTol, but the
+// delay of the error estimate is configurable.
+//
+// The Hestenes-Stiefel estimate of the error at one
+// iteration is only available delay iterations later,
+// so delay extra iterations are always run.
+// A larger delay gives a tighter estimate.
+//
+// This panics if delay is not positive.
+func SolveEnergyTolDelay(t LinTran, b linalg.Vector, energyTol float64,
+	delay int) linalg.Vector {
+	if delay <= 0 {
+		panic("delay must be positive")
 	}
+	opts := &SolveOptions{energyTol: energyTol, energyDelay: delay}
+	return solve(t, b, opts).Solution
+}
 
-	return solution
+// SolveTikhonov solves the regularized system
+// (t + lambda*I)*x = b.
+//
+// When t is symmetric positive semidefinite and lambda
+// is positive, the regularized operator is positive
+// definite, so this is robust even if t is singular.
+// With lambda = 0, this is just SolvePrec without a
+// preconditioner.
+//
+// This panics if lambda is negative.
+func SolveTikhonov(t LinTran, b linalg.Vector, lambda, prec float64) linalg.Vector {
+	if lambda < 0 {
+		panic("lambda must be non-negative")
+	}
+	op := t
+	if lambda > 0 {
+		op = SumTran(t, ScaledTran(lambda, IdentityTran(t.Dim())))
+	}
+	return SolvePrec(op, nil, b, prec)
+}
+
+// SolveReturningKrylov is like SolvePrec without a
+// preconditioner, but it also returns the search
+// directions generated during the solve.
+//
+// The directions are t-orthogonal and span the Krylov
+// subspace which CG explored, so they can be reused to
+// cheaply solve nearby systems.
+// Keeping them costs one extra vector per iteration.
+// The periodic residual refresh still happens, so the
+// directions are only t-orthogonal up to rounding
+// error.
+func SolveReturningKrylov(t LinTran, b linalg.Vector,
+	prec float64) (linalg.Vector, []linalg.Vector) {
+	var directions []linalg.Vector
+	opts := &SolveOptions{Tolerance: prec, directions: &directions}
+	return solve(t, b, opts).Solution, directions
+}
+
+// SolveChecked is like SolvePrec without a
+// preconditioner, but it fails with a
+// *NotDefiniteError if it encounters a search
+// direction p for which p'*t*p is not positive.
+// Such a direction proves that t is not
+// positive-definite.
+func SolveChecked(t LinTran, b linalg.Vector, prec float64) (linalg.Vector, error) {
+	res := solve(t, b, &SolveOptions{Tolerance: prec, checkDefinite: true})
+	return res.Solution, res.err
+}
+
+// SolveSafe is like SolvePrec without a
+// preconditioner, but it checks the residual for NaN
+// and infinite values after every update.
+// It fails as soon as one appears, with an error which
+// names the iteration, rather than returning garbage.
+//
+// The check costs a scan of the residual per
+// iteration, so the other solvers do not perform it.
+func SolveSafe(t LinTran, b linalg.Vector, prec float64) (linalg.Vector, error) {
+	res := solve(t, b, &SolveOptions{Tolerance: prec, checkFinite: true})
+	return res.Solution, res.err
 }
 
 // SolvePrec is like SolveStoppable, but it does not
@@ -73,6 +379,55 @@ func SolvePrec(t, precond LinTran, b linalg.Vector, prec float64) linalg.Vector
 	return SolveStoppable(t, precond, b, prec, nil)
 }
 
+// updateResidual moves residual to account for a step
+// of size step along a direction whose image under t
+// is appliedDir.
+//
+// Every interval iterations, the true residual b-t*x
+// is recomputed instead, to prevent rounding errors
+// from accumulating.
+// If interval is not positive, it never is.
+// If scratch is non-nil, it may be used as the output
+// of t and may alias appliedDir.
+func updateResidual(t LinTran, b, solution, residual, appliedDir linalg.Vector,
+	step float64, iters, interval int, scratch linalg.Vector) {
+	if interval > 0 && iters != 0 && (iters%interval) == 0 {
+		copy(residual, b)
+		if scratch != nil {
+			residual.AddScaled(applyInto(t, scratch, solution), -1)
+		} else {
+			residual.AddScaled(t.Apply(solution), -1)
+		}
+	} else {
+		residual.AddScaled(appliedDir, -step)
+	}
+}
+
+// nonFiniteError returns an error if any component of
+// the residual is NaN or infinite.
+func nonFiniteError(residual linalg.Vector, iters int) error {
+	for i, x := range residual {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return fmt.Errorf("non-finite residual component %d at iteration %d", i, iters)
+		}
+	}
+	return nil
+}
+
+// detailedResult builds a SolveResult for a solver
+// which does not track the state that solve does.
+// It applies t once to find the final residual.
+func detailedResult(t LinTran, b, solution linalg.Vector, iters int,
+	prec float64) SolveResult {
+	residual := b.Copy().Sub(t.Apply(solution)).NormInf()
+	return SolveResult{
+		Solution:      solution,
+		Iterations:    iters,
+		FinalResidual: residual,
+		Converged:     residual <= prec,
+	}
+}
+
 func allZero(v linalg.Vector) bool {
 	for _, x := range v {
 		if x != 0 {
@@ -81,3 +436,205 @@ func allZero(v linalg.Vector) bool {
 	}
 	return true
 }
+
+func solve(t LinTran, b linalg.Vector, opts *SolveOptions) SolveResult {
+	prec := math.Max(opts.Tolerance, opts.RelativeTolerance*b.MaxAbs())
+	m := opts.Preconditioner
+	if m == nil {
+		m = identityPreconditioner{}
+	}
+
+	ws := opts.workspace
+	if ws == nil {
+		ws = &Solver{}
+	}
+	residual, conjVec, solution, applied := ws.buffers(t.Dim())
+
+	residualNorm := linalg.Vector.NormInf
+	switch opts.ResidualNorm {
+	case Norm2:
+		residualNorm = linalg.Vector.Norm
+	case NormRelative:
+		residualNorm = func(r linalg.Vector) float64 {
+			return r.MaxAbsRelative(b)
+		}
+	}
+
+	refreshInterval := opts.ResidualRefreshInterval
+	if refreshInterval == 0 {
+		refreshInterval = residualUpdateFrequency
+	}
+
+	dot := linalg.Vector.DotKahan
+	if opts.FastDot {
+		dot = linalg.Vector.DotFast
+	}
+	var lastResidualDot float64
+
+	copy(residual, b)
+	if opts.InitialGuess != nil {
+		if len(opts.InitialGuess) != t.Dim() {
+			panic("dimension mismatch")
+		}
+		copy(solution, opts.InitialGuess)
+		residual.AddScaled(applyInto(t, applied, solution), -1)
+	} else {
+		for i := range solution {
+			solution[i] = 0
+		}
+	}
+
+	var iters int
+	var err error
+	converged := true
+	stagnated := false
+	floorReached := false
+	lastRefreshNorm := residualNorm(residual)
+
+	var energyTerms []float64
+	if opts.energyTol > 0 {
+		energyTerms = make([]float64, 0, opts.energyDelay+1)
+	}
+
+	var recentNorms []float64
+	if opts.StagnationWindow > 0 {
+		recentNorms = make([]float64, 0, opts.StagnationWindow+1)
+		recentNorms = append(recentNorms, residual.Mag())
+	}
+
+	if opts.checkFinite {
+		if err = nonFiniteError(residual, 0); err != nil {
+			converged = false
+		}
+	}
+
+	for err == nil && residualNorm(residual) > prec {
+		if opts.MaxIter > 0 && iters >= opts.MaxIter {
+			converged = false
+			break
+		}
+		z := m.ApplyInverse(residual)
+		residualDot := dot(z, residual)
+		var beta float64
+		restart := opts.RestartInterval > 0 && iters%opts.RestartInterval == 0
+		if iters == 0 || restart {
+			copy(conjVec, z)
+		} else {
+			beta = residualDot / lastResidualDot
+			conjVec.Scale(beta).Add(z)
+		}
+		lastResidualDot = residualDot
+		if allZero(conjVec) {
+			converged = false
+			break
+		}
+		appliedConj := applyInto(t, applied, conjVec)
+		curvature := dot(conjVec, appliedConj)
+		if opts.checkDefinite && curvature <= 0 {
+			converged = false
+			err = &NotDefiniteError{Iteration: iters, Curvature: curvature}
+			break
+		}
+		optimalDistance := residualDot / curvature
+
+		if opts.directions != nil {
+			*opts.directions = append(*opts.directions, conjVec.Copy())
+		}
+		if opts.coefficients != nil {
+			opts.coefficients.add(optimalDistance, beta, iters == 0)
+		}
+
+		solution.AddScaled(conjVec, optimalDistance)
+		if energyTerms != nil {
+			// The Hestenes-Stiefel estimate of the squared
+			// t-norm error energyDelay iterations ago is the
+			// sum of the last energyDelay terms.
+			energyTerms = append(energyTerms, optimalDistance*residualDot)
+			if len(energyTerms) > opts.energyDelay {
+				energyTerms = energyTerms[1:]
+			}
+			var sum float64
+			for _, term := range energyTerms {
+				sum += term
+			}
+			if len(energyTerms) == opts.energyDelay && math.Sqrt(sum) <= opts.energyTol {
+				iters++
+				break
+			}
+		}
+		if opts.changeTol > 0 && math.Abs(optimalDistance)*conjVec.Mag() < opts.changeTol {
+			iters++
+			break
+		}
+
+		refreshing := refreshInterval > 0 && iters != 0 && iters%refreshInterval == 0
+		if opts.residualGap != nil && refreshing {
+			recurrence := residual.Copy().AddScaled(appliedConj, -optimalDistance)
+			opts.residualGap.recurrence = append(opts.residualGap.recurrence,
+				recurrence.NormInf())
+		}
+		updateResidual(t, b, solution, residual, appliedConj, optimalDistance,
+			iters, refreshInterval, applied)
+		if opts.residualGap != nil && refreshing {
+			opts.residualGap.actual = append(opts.residualGap.actual, residual.NormInf())
+		}
+		iters++
+		if opts.DetectResidualFloor && refreshing {
+			refreshNorm := residualNorm(residual)
+			if refreshNorm > prec && !(refreshNorm < residualFloorImprovement*lastRefreshNorm) {
+				converged = false
+				floorReached = true
+				break
+			}
+			lastRefreshNorm = refreshNorm
+		}
+		if opts.checkFinite {
+			if err = nonFiniteError(residual, iters); err != nil {
+				converged = false
+				break
+			}
+		}
+
+		if opts.history != nil {
+			*opts.history = append(*opts.history, residual.Mag())
+		}
+		if recentNorms != nil {
+			if len(recentNorms) == cap(recentNorms) {
+				copy(recentNorms, recentNorms[1:])
+				recentNorms = recentNorms[:len(recentNorms)-1]
+			}
+			recentNorms = append(recentNorms, residual.Mag())
+			if len(recentNorms) == cap(recentNorms) {
+				oldNorm := recentNorms[0]
+				if residual.Mag() > oldNorm*(1-opts.StagnationTol) &&
+					residualNorm(residual) > prec {
+					converged = false
+					stagnated = true
+					break
+				}
+			}
+		}
+		if opts.Observe != nil && !opts.Observe(iters, residual.NormInf()) {
+			converged = residualNorm(residual) <= prec
+			break
+		}
+
+		if cancelled(opts.Cancel) {
+			converged = residualNorm(residual) <= prec
+			break
+		}
+	}
+
+	copy(residual, b)
+	residual.Sub(applyInto(t, applied, solution))
+	return SolveResult{
+		Solution:      solution,
+		Iterations:    iters,
+		FinalResidual: residual.NormInf(),
+		Converged:     converged,
+		Stagnated:     stagnated,
+		FloorReached:  floorReached,
+
+		err: err,
+	}
+}
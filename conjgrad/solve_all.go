@@ -0,0 +1,46 @@
+package conjgrad
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// SolveAll solves t*x = b for every b in bs, running
+// up to concurrency independent solves at once.
+//
+// Each worker reuses its own Solver, and the
+// solutions are returned in the same order as bs.
+// If concurrency is 0 or negative, runtime.NumCPU()
+// is used.
+//
+// Since t.Apply is called from multiple goroutines,
+// it must be safe to call concurrently.
+func SolveAll(t LinTran, bs []linalg.Vector, prec float64,
+	concurrency int) []linalg.Vector {
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	indices := make(chan int, len(bs))
+	for i := range bs {
+		indices <- i
+	}
+	close(indices)
+
+	res := make([]linalg.Vector, len(bs))
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(bs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var solver Solver
+			for idx := range indices {
+				res[idx] = solver.Solve(t, bs[idx], prec).Copy()
+			}
+		}()
+	}
+	wg.Wait()
+	return res
+}
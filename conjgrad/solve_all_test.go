@@ -0,0 +1,26 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveAll(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	bs := make([]linalg.Vector, 20)
+	expected := make([]linalg.Vector, len(bs))
+	for i := range bs {
+		bs[i] = b.Copy().Scale(float64(i + 1))
+		expected[i] = realSolution.Copy().Scale(float64(i + 1))
+	}
+	for _, concurrency := range []int{0, 1, 3} {
+		solutions := SolveAll(lt, bs, 1e-10, concurrency)
+		if len(solutions) != len(bs) {
+			t.Fatalf("expected %d solutions but got %d", len(bs), len(solutions))
+		}
+		for i, solution := range solutions {
+			checkSolution(t, solution, expected[i])
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package conjgrad
 
 import (
+	"context"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/unixpickle/num-analysis/linalg"
 )
@@ -73,3 +75,464 @@ func TestSolvePrecond(t *testing.T) {
 		}
 	}
 }
+
+func TestSolvePreconditioned(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	diag := make(diagonalInverse, len(b))
+	for i := range diag {
+		diag[i] = 1 / lt.M.Get(i, i)
+	}
+	solution := SolvePreconditioned(lt, diag, b, 1e-8, nil)
+	checkSolution(t, solution, realSolution)
+}
+
+type diagonalInverse linalg.Vector
+
+func (d diagonalInverse) ApplyInverse(r linalg.Vector) linalg.Vector {
+	res := make(linalg.Vector, len(r))
+	for i, x := range r {
+		res[i] = x * d[i]
+	}
+	return res
+}
+
+func testProblem() (lt MatLinTran, b, solution linalg.Vector) {
+	problem := &linalg.Matrix{
+		Rows: 5,
+		Cols: 5,
+		Data: []float64{
+			2.298579079508162, 0.429201370738324, 0.513159695114135, 0.890572491575309,
+			-0.917105776402277,
+			0.429201370738324, 5.342630790304731, 3.551802515565508, 1.540530879076629,
+			1.805946316093086,
+			0.513159695114135, 3.551802515565508, 3.846999494537341, 1.996350132566954,
+			1.759376365048858,
+			0.890572491575309, 1.540530879076629, 1.996350132566954, 1.558637048015492,
+			1.576812350190962,
+			-0.917105776402277, 1.805946316093086, 1.759376365048858, 1.576812350190962,
+			4.727648739788367,
+		},
+	}
+	lt = MatLinTran{M: problem}
+	b = linalg.Vector{0.3298532642882215, 0.0330271613168995, 0.7816739111835319,
+		0.2310847231465032, 0.3820560329158281}
+	solution = linalg.Vector{489.504834645279, -185.447583828038, 534.674948145785,
+		-1128.308042204228, 343.226374596134}
+	return
+}
+
+func checkSolution(t *testing.T, actual, expected linalg.Vector) {
+	for i, x := range expected {
+		if a := actual[i]; math.Abs(x-a) > 1e-5 || math.IsNaN(a) {
+			t.Error("expected solution", expected, "but got", actual)
+			break
+		}
+	}
+}
+
+func TestSolveDetailed(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	res := SolveDetailed(lt, b, 1e-8)
+	checkSolution(t, res.Solution, realSolution)
+	if !res.Converged {
+		t.Error("solve should have converged")
+	}
+	if res.Iterations == 0 {
+		t.Error("unexpected iteration count")
+	}
+	if res.FinalResidual > 1e-7 {
+		t.Error("unexpected final residual:", res.FinalResidual)
+	}
+}
+
+func TestSolveRelative(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution := SolveRelative(lt, b.Copy().Scale(1e-3), 1e-8, nil)
+	checkSolution(t, solution.Scale(1e3), realSolution)
+
+	zero := SolveRelative(lt, make(linalg.Vector, len(b)), 1e-8, nil)
+	if !allZero(zero) {
+		t.Error("expected zero solution but got", zero)
+	}
+}
+
+func TestSolveMaxIter(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	res := SolveWith(lt, b, SolveOptions{Tolerance: 1e-8, MaxIter: 2})
+	if res.Iterations != 2 {
+		t.Error("expected 2 iterations but got", res.Iterations)
+	}
+	if res.Converged {
+		t.Error("solve should not have converged")
+	}
+	res = SolveWith(lt, b, SolveOptions{Tolerance: 1e-8})
+	checkSolution(t, res.Solution, realSolution)
+}
+
+func TestSolveStagnation(t *testing.T) {
+	lt, b, realSolution := testProblem()
+
+	// Demanding a near-total reduction on every
+	// iteration makes any solve look stagnant.
+	res := SolveWith(lt, b, SolveOptions{
+		Tolerance:        1e-8,
+		StagnationWindow: 1,
+		StagnationTol:    1 - 1e-12,
+	})
+	if !res.Stagnated || res.Converged {
+		t.Errorf("expected stagnation (stagnated=%v, converged=%v)",
+			res.Stagnated, res.Converged)
+	}
+
+	res = SolveWith(lt, b, SolveOptions{
+		Tolerance:        1e-8,
+		StagnationWindow: 5,
+		StagnationTol:    0,
+	})
+	if res.Stagnated || !res.Converged {
+		t.Errorf("unexpected stagnation (stagnated=%v, converged=%v)",
+			res.Stagnated, res.Converged)
+	}
+	checkSolution(t, res.Solution, realSolution)
+}
+
+func TestSolveContext(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution, err := SolveContext(context.Background(), lt, b, 1e-8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, solution, realSolution)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := SolveContext(ctx, lt, b, 1e-8); err != context.Canceled {
+		t.Error("expected context.Canceled but got", err)
+	}
+}
+
+func TestSolveTimeout(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution, converged := SolveTimeout(lt, b, 1e-8, time.Hour)
+	if !converged {
+		t.Error("solve did not converge")
+	}
+	checkSolution(t, solution, realSolution)
+
+	band := NewSymBand(1000, 1)
+	for i := 0; i < 1000; i++ {
+		band.SetBand(i, 0, 2.001)
+		if i+1 < 1000 {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	b = linalg.RandVector(1000)
+	solution, converged = SolveTimeout(band, b, 1e-10, 0)
+	if converged {
+		t.Error("solve should not converge without a budget")
+	}
+	if len(solution) != 1000 {
+		t.Error("bad solution length", len(solution))
+	}
+}
+
+func TestSolveGuess(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	guess := realSolution.Copy()
+	guess[0] += 1
+	res := solve(lt, b, &SolveOptions{Tolerance: 1e-8, InitialGuess: guess})
+	checkSolution(t, res.Solution, realSolution)
+	if guess[0] != realSolution[0]+1 {
+		t.Error("initial guess was modified")
+	}
+	checkSolution(t, SolveGuess(lt, b, guess, 1e-8), realSolution)
+}
+
+func TestSolveObserved(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	var calls int
+	solution := SolveObserved(lt, b, 1e-8, func(iter int, residualNorm float64) bool {
+		calls++
+		if iter != calls {
+			t.Error("unexpected iteration", iter, "for call", calls)
+		}
+		return true
+	})
+	checkSolution(t, solution, realSolution)
+	if calls == 0 {
+		t.Error("observe was never called")
+	}
+
+	calls = 0
+	SolveObserved(lt, b, 1e-8, func(iter int, residualNorm float64) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Error("expected solve to stop after one call but got", calls)
+	}
+}
+
+func TestSolveWithHistory(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution, history := SolveWithHistory(lt, b, 1e-8)
+	checkSolution(t, solution, realSolution)
+	res := SolveDetailed(lt, b, 1e-8)
+	if len(history) != res.Iterations {
+		t.Error("expected", res.Iterations, "entries but got", len(history))
+	}
+}
+
+func TestSolveCountingIters(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution, iters := SolveCountingIters(lt, b, 1e-8)
+	checkSolution(t, solution, realSolution)
+	if expected := SolveDetailed(lt, b, 1e-8).Iterations; iters != expected {
+		t.Errorf("expected %d iterations but got %d", expected, iters)
+	}
+	if _, iters := SolveCountingIters(lt, linalg.Vector{1e-9, 0, 0, 0, 0}, 1e-8); iters != 0 {
+		t.Errorf("expected 0 iterations but got %d", iters)
+	}
+}
+
+func TestSolveChecked(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution, err := SolveChecked(lt, b, 1e-8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, solution, realSolution)
+
+	lt1, b1, _ := indefiniteProblem()
+	_, err = SolveChecked(lt1, b1, 1e-8)
+	if _, ok := err.(*NotDefiniteError); !ok {
+		t.Error("expected NotDefiniteError but got", err)
+	}
+}
+
+func TestSolveSafe(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution, err := SolveSafe(lt, b, 1e-8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, solution, realSolution)
+
+	var applies int
+	broken := NewFuncTran(5, func(v linalg.Vector) linalg.Vector {
+		applies++
+		res := lt.Apply(v)
+		if applies > 2 {
+			res[1] = math.NaN()
+		}
+		return res
+	})
+	if _, err := SolveSafe(broken, b, 1e-8); err == nil {
+		t.Error("expected error for NaN operator")
+	}
+
+	b[0] = math.Inf(1)
+	if _, err := SolveSafe(lt, b, 1e-8); err == nil {
+		t.Error("expected error for infinite right-hand side")
+	}
+}
+
+func BenchmarkSolve(b *testing.B) {
+	lt, rhs, _ := testProblem()
+	mat := &DenseMatrix{Rows: 5, Cols: 5, Data: lt.M.Data}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		SolvePrec(mat, nil, rhs, 1e-8)
+	}
+}
+
+func TestSolveFastDot(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	res := SolveWith(lt, b, SolveOptions{Tolerance: 1e-8, FastDot: true})
+	checkSolution(t, res.Solution, realSolution)
+}
+
+func TestSolveUntilStable(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution := SolveUntilStable(lt, b, 1e-10)
+	checkSolution(t, solution, realSolution)
+}
+
+func TestSolveTikhonov(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	checkSolution(t, SolveTikhonov(lt, b, 0, 1e-10), realSolution)
+
+	// A singular diagonal operator becomes solvable.
+	diag := Diagonal(linalg.Vector{0, 1, 3})
+	solution := SolveTikhonov(diag, linalg.Vector{1, 2, 4}, 1, 1e-10)
+	checkSolution(t, solution, linalg.Vector{1, 1, 1})
+}
+
+func TestSolveReturningKrylov(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution, directions := SolveReturningKrylov(lt, b, 1e-10)
+	checkSolution(t, solution, realSolution)
+	if len(directions) == 0 {
+		t.Fatal("no directions returned")
+	}
+
+	// Projecting onto the directions solves the system.
+	projected := make(linalg.Vector, len(b))
+	for i, dir := range directions {
+		applied := lt.Apply(dir)
+		for j := 0; j < i; j++ {
+			cos := applied.Dot(directions[j]) /
+				(EnergyNorm(lt, dir) * EnergyNorm(lt, directions[j]))
+			if math.Abs(cos) > 1e-6 {
+				t.Errorf("directions %d and %d are not conjugate (%g)", i, j, cos)
+			}
+		}
+		projected.AddScaled(dir, dir.Dot(b)/dir.Dot(applied))
+	}
+	checkSolution(t, projected, realSolution)
+}
+
+func TestSolveResidualNorm(t *testing.T) {
+	// Both solves produce the same iterates, and the
+	// 2-norm is never smaller than the infinity norm, so
+	// the 2-norm bound cannot stop any sooner.
+	diag := Diagonal{1, 2, 3, 4, 5, 6, 7, 8}
+	b := linalg.Vector{1, 1, 1, 1, 1, 1, 1, 1}
+	for _, tol := range []float64{1e-1, 1e-3, 1e-6} {
+		infRes := SolveWith(diag, b, SolveOptions{Tolerance: tol})
+		twoRes := SolveWith(diag, b, SolveOptions{Tolerance: tol, ResidualNorm: Norm2})
+		if twoRes.Iterations < infRes.Iterations {
+			t.Errorf("tolerance %g: 2-norm took %d iterations, inf-norm took %d",
+				tol, twoRes.Iterations, infRes.Iterations)
+		}
+		residual := b.Copy().Sub(diag.Apply(twoRes.Solution))
+		if residual.Norm() > tol {
+			t.Errorf("tolerance %g: 2-norm residual is %g", tol, residual.Norm())
+		}
+	}
+}
+
+func TestSolveRelativeNorm(t *testing.T) {
+	// The small components of b need as much relative
+	// accuracy as the large ones.
+	diag := Diagonal{1, 2, 3, 4, 5, 6, 7, 8}
+	b := linalg.Vector{1e-9, 1, 1e6, 1, 1e-6, 1, 1, 1e3}
+	res := SolveWith(diag, b, SolveOptions{Tolerance: 1e-6, ResidualNorm: NormRelative})
+	residual := b.Copy().Sub(diag.Apply(res.Solution))
+	if n := residual.MaxAbsRelative(b); n > 1e-6 {
+		t.Errorf("relative residual is %g", n)
+	}
+}
+
+func TestSolveDefaultTolerance(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution := Solve(lt, b, 0)
+	checkSolution(t, solution, realSolution)
+	residual := b.Copy().Sub(lt.Apply(solution))
+	if residual.NormInf() > 1e-10*b.NormInf() {
+		t.Errorf("unexpected residual %g", residual.NormInf())
+	}
+
+	checkSolution(t, Solve(lt, b, 1e-8), realSolution)
+	checkSolution(t, Solve(lt, make(linalg.Vector, 5), 0), make(linalg.Vector, 5))
+}
+
+func TestSolveResidualRefreshInterval(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	for _, interval := range []int{-1, 0, 1, 3} {
+		res := SolveWith(lt, b, SolveOptions{Tolerance: 1e-8, ResidualRefreshInterval: interval})
+		if !res.Converged {
+			t.Errorf("interval %d: solve did not converge", interval)
+		}
+		checkSolution(t, res.Solution, realSolution)
+	}
+}
+
+func TestSolveRestartInterval(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	plain := SolveWith(lt, b, SolveOptions{Tolerance: 1e-11})
+	for _, interval := range []int{-1, 1, 3} {
+		res := SolveWith(lt, b, SolveOptions{Tolerance: 1e-11, RestartInterval: interval})
+		checkSolution(t, res.Solution, realSolution)
+		if interval <= 0 && res.Iterations != plain.Iterations {
+			t.Errorf("interval %d: took %d iterations but expected %d",
+				interval, res.Iterations, plain.Iterations)
+		}
+	}
+}
+
+func TestSolveWithResidualGap(t *testing.T) {
+	band := NewSymBand(60, 1)
+	for i := 0; i < 60; i++ {
+		band.SetBand(i, 0, 2.01)
+		if i+1 < 60 {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	expected := linalg.RandVector(60)
+	solution, recurrence, actual := SolveWithResidualGap(band, band.Apply(expected), 1e-10)
+	checkSolution(t, solution, expected)
+	if len(recurrence) == 0 || len(recurrence) != len(actual) {
+		t.Fatalf("unexpected lengths %d and %d", len(recurrence), len(actual))
+	}
+	for i, r := range recurrence {
+		if math.Abs(r-actual[i]) > 1e-8 {
+			t.Errorf("refresh %d: recurrence %g but actual %g", i, r, actual[i])
+		}
+	}
+}
+
+func TestSolveDetectResidualFloor(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	res := SolveWith(lt, b, SolveOptions{
+		Tolerance:           0,
+		MaxIter:             10000,
+		DetectResidualFloor: true,
+	})
+	if !res.FloorReached || res.Converged {
+		t.Fatalf("expected the floor to be reached (result %+v)", res)
+	}
+	if res.Iterations >= 10000 {
+		t.Error("solve ran out the iteration budget")
+	}
+	checkSolution(t, res.Solution, realSolution)
+
+	res = SolveWith(lt, b, SolveOptions{Tolerance: 1e-8, DetectResidualFloor: true})
+	if res.FloorReached || !res.Converged {
+		t.Error("reachable tolerance was reported as a floor")
+	}
+}
+
+func TestSolveWithOptions(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	guess := realSolution.Copy()
+	guess[1] += 1
+	var observed int
+	res := SolveWith(lt, b, SolveOptions{
+		RelativeTolerance: 1e-10,
+		Preconditioner:    NewJacobiFromLinTran(lt),
+		InitialGuess:      guess,
+		Observe: func(iter int, residualNorm float64) bool {
+			observed = iter
+			return true
+		},
+	})
+	if !res.Converged {
+		t.Fatal("solve did not converge")
+	}
+	if res.FinalResidual > 1e-10*b.MaxAbs()*1.01 {
+		t.Errorf("unexpected residual %g", res.FinalResidual)
+	}
+	if observed != res.Iterations {
+		t.Errorf("observed %d iterations but ran %d", observed, res.Iterations)
+	}
+	checkSolution(t, res.Solution, realSolution)
+
+	cancelChan := make(chan struct{})
+	close(cancelChan)
+	res = SolveWith(lt, b, SolveOptions{Tolerance: 1e-8, Cancel: cancelChan})
+	if res.Converged || res.Iterations != 1 {
+		t.Errorf("expected one iteration before cancelling but got %d", res.Iterations)
+	}
+}
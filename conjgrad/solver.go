@@ -0,0 +1,39 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// A Solver solves systems of linear equations while
+// reusing its scratch memory from one solve to the
+// next.
+//
+// The same Solver may be used with different LinTrans.
+// If the dimension changes between solves, the scratch
+// memory is reallocated.
+//
+// A Solver should not be used from more than one
+// goroutine at once.
+type Solver struct {
+	residual linalg.Vector
+	conjVec  linalg.Vector
+	solution linalg.Vector
+	applied  linalg.Vector
+}
+
+// Solve is like SolvePrec without a preconditioner.
+//
+// The returned vector belongs to the Solver, and it
+// will be overwritten by the next call to Solve.
+// Copy it if you need to keep it around.
+func (s *Solver) Solve(t LinTran, b linalg.Vector, prec float64) linalg.Vector {
+	return solve(t, b, &SolveOptions{Tolerance: prec, workspace: s}).Solution
+}
+
+func (s *Solver) buffers(dim int) (residual, conjVec, solution, applied linalg.Vector) {
+	if len(s.solution) != dim {
+		s.residual = make(linalg.Vector, dim)
+		s.conjVec = make(linalg.Vector, dim)
+		s.solution = make(linalg.Vector, dim)
+		s.applied = make(linalg.Vector, dim)
+	}
+	return s.residual, s.conjVec, s.solution, s.applied
+}
@@ -0,0 +1,38 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolver(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	var solver Solver
+	for i := 0; i < 3; i++ {
+		checkSolution(t, solver.Solve(lt, b, 1e-8), realSolution)
+	}
+	small := Diagonal{2, 4}
+	checkSolution(t, solver.Solve(small, linalg.Vector{1, 1}, 1e-8), linalg.Vector{0.5, 0.25})
+	checkSolution(t, solver.Solve(lt, b, 1e-8), realSolution)
+
+	mat := &DenseMatrix{Rows: 5, Cols: 5, Data: lt.M.Data}
+	solver.Solve(mat, b, 1e-8)
+	allocs := testing.AllocsPerRun(10, func() {
+		solver.Solve(mat, b, 1e-8)
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations but got %v", allocs)
+	}
+}
+
+func BenchmarkSolver(b *testing.B) {
+	lt, rhs, _ := testProblem()
+	mat := &DenseMatrix{Rows: 5, Cols: 5, Data: lt.M.Data}
+	var solver Solver
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		solver.Solve(mat, rhs, 1e-8)
+	}
+}
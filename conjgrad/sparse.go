@@ -0,0 +1,196 @@
+package conjgrad
+
+import (
+	"sort"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// SparseCSR is a square sparse matrix stored in
+// compressed sparse row format.
+type SparseCSR struct {
+	dim int
+
+	// values and colIndices store the non-zero
+	// entries row by row, with the entries of each
+	// row sorted by column.
+	values     []float64
+	colIndices []int
+
+	// rowPtr[i] is the index in values of the first
+	// entry in row i, and rowPtr[dim] is the total
+	// number of entries.
+	rowPtr []int
+}
+
+type cooEntry struct {
+	row int
+	col int
+	val float64
+}
+
+// NewSparseCSR creates a dim by dim sparse matrix
+// from a list of (row, column, value) triplets.
+//
+// The triplets may be given in any order.
+// If more than one triplet refers to the same
+// entry, their values are summed.
+func NewSparseCSR(dim int, rows, cols []int, vals []float64) *SparseCSR {
+	if len(rows) != len(cols) || len(rows) != len(vals) {
+		panic("dimension mismatch")
+	}
+	entries := make([]cooEntry, len(rows))
+	for i, row := range rows {
+		if row < 0 || row >= dim || cols[i] < 0 || cols[i] >= dim {
+			panic("index out of bounds")
+		}
+		entries[i] = cooEntry{row: row, col: cols[i], val: vals[i]}
+	}
+	return newSparseCSR(dim, entries)
+}
+
+// A COOBuilder assembles a SparseCSR from a list of
+// (row, column, value) triplets, which is convenient
+// for finite-element style assembly.
+type COOBuilder struct {
+	dim     int
+	entries []cooEntry
+}
+
+// NewCOOBuilder creates an empty builder for a dim by
+// dim matrix.
+func NewCOOBuilder(dim int) *COOBuilder {
+	return &COOBuilder{dim: dim}
+}
+
+// Add adds v to the entry at row i and column j.
+//
+// Entries may be added in any order, and entries which
+// are added more than once are summed.
+func (c *COOBuilder) Add(i, j int, v float64) {
+	if i < 0 || i >= c.dim || j < 0 || j >= c.dim {
+		panic("index out of bounds")
+	}
+	c.entries = append(c.entries, cooEntry{row: i, col: j, val: v})
+}
+
+// Build creates a SparseCSR from the entries added so
+// far.
+// The builder may continue to be used afterwards.
+func (c *COOBuilder) Build() *SparseCSR {
+	entries := append([]cooEntry{}, c.entries...)
+	return newSparseCSR(c.dim, entries)
+}
+
+func newSparseCSR(dim int, entries []cooEntry) *SparseCSR {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].row != entries[j].row {
+			return entries[i].row < entries[j].row
+		}
+		return entries[i].col < entries[j].col
+	})
+
+	res := &SparseCSR{dim: dim, rowPtr: make([]int, dim+1)}
+	for i, entry := range entries {
+		if i > 0 && entries[i-1].row == entry.row && entries[i-1].col == entry.col {
+			res.values[len(res.values)-1] += entry.val
+			continue
+		}
+		res.values = append(res.values, entry.val)
+		res.colIndices = append(res.colIndices, entry.col)
+		res.rowPtr[entry.row+1] = len(res.values)
+	}
+	for i := 1; i <= dim; i++ {
+		if res.rowPtr[i] < res.rowPtr[i-1] {
+			res.rowPtr[i] = res.rowPtr[i-1]
+		}
+	}
+	return res
+}
+
+// Dim returns the number of rows (and columns) in
+// the matrix.
+func (s *SparseCSR) Dim() int {
+	return s.dim
+}
+
+// NonZeros returns the number of stored entries.
+func (s *SparseCSR) NonZeros() int {
+	return len(s.values)
+}
+
+// At returns the entry at the i-th row and j-th
+// column.
+func (s *SparseCSR) At(i, j int) float64 {
+	start, end := s.rowPtr[i], s.rowPtr[i+1]
+	idx := start + sort.SearchInts(s.colIndices[start:end], j)
+	if idx < end && s.colIndices[idx] == j {
+		return s.values[idx]
+	}
+	return 0
+}
+
+// Apply returns the product of the matrix and v.
+func (s *SparseCSR) Apply(v linalg.Vector) linalg.Vector {
+	res := make(linalg.Vector, s.dim)
+	s.ApplyInto(res, v)
+	return res
+}
+
+// ApplyInto stores the product of the matrix and src
+// in dst.
+func (s *SparseCSR) ApplyInto(dst, src linalg.Vector) {
+	if len(src) != s.dim || len(dst) != s.dim {
+		panic("dimension mismatch")
+	}
+	for row := range dst {
+		var sum float64
+		for idx := s.rowPtr[row]; idx < s.rowPtr[row+1]; idx++ {
+			sum += s.values[idx] * src[s.colIndices[idx]]
+		}
+		dst[row] = sum
+	}
+}
+
+// ApplyTranspose returns the product of the transpose
+// of the matrix and v.
+func (s *SparseCSR) ApplyTranspose(v linalg.Vector) linalg.Vector {
+	if len(v) != s.dim {
+		panic("dimension mismatch")
+	}
+	res := make(linalg.Vector, s.dim)
+	for row, x := range v {
+		for idx := s.rowPtr[row]; idx < s.rowPtr[row+1]; idx++ {
+			res[s.colIndices[idx]] += s.values[idx] * x
+		}
+	}
+	return res
+}
+
+// Symmetrize creates a symmetric matrix from the
+// upper triangle (including the diagonal) of s by
+// mirroring it into the lower triangle.
+//
+// Entries below the diagonal of s are ignored.
+func (s *SparseCSR) Symmetrize() *SparseCSR {
+	var entries []cooEntry
+	for row := 0; row < s.dim; row++ {
+		for idx := s.rowPtr[row]; idx < s.rowPtr[row+1]; idx++ {
+			col := s.colIndices[idx]
+			if col < row {
+				continue
+			}
+			entries = append(entries, cooEntry{row: row, col: col, val: s.values[idx]})
+			if col != row {
+				entries = append(entries, cooEntry{row: col, col: row, val: s.values[idx]})
+			}
+		}
+	}
+	return newSparseCSR(s.dim, entries)
+}
+
+func (s *SparseCSR) iterRow(row int, f func(col int, val float64)) {
+	for idx := s.rowPtr[row]; idx < s.rowPtr[row+1]; idx++ {
+		f(s.colIndices[idx], s.values[idx])
+	}
+}
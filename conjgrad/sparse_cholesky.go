@@ -0,0 +1,181 @@
+package conjgrad
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// SparseCholeskyFactor is the Cholesky factorization
+// P*A*P' = L*L' of a sparse symmetric positive-definite
+// matrix A, where P is a fill-reducing permutation.
+type SparseCholeskyFactor struct {
+	perm []int
+
+	// first[i] is the column of the first entry stored
+	// for row i of L, and rows[i] stores the entries of
+	// row i from that column through the diagonal.
+	first []int
+	rows  [][]float64
+}
+
+// SparseCholesky computes the Cholesky factorization
+// of a sparse symmetric positive-definite matrix.
+//
+// The rows and columns are first reordered with the
+// reverse Cuthill-McKee algorithm, which keeps the
+// non-zeros close to the diagonal.
+// The factor is then stored as a profile: every row
+// of L is dense from its first non-zero entry through
+// the diagonal, since no fill can occur before it.
+//
+// Both triangles of a must be stored.
+// An error is returned if a non-positive pivot is
+// encountered, meaning that a is not positive-definite.
+func SparseCholesky(a *SparseCSR) (*SparseCholeskyFactor, error) {
+	perm := reverseCuthillMcKee(a)
+	inv := make([]int, len(perm))
+	for i, p := range perm {
+		inv[p] = i
+	}
+
+	factor := &SparseCholeskyFactor{
+		perm:  perm,
+		first: make([]int, a.dim),
+		rows:  make([][]float64, a.dim),
+	}
+	for i, p := range perm {
+		first := i
+		for idx := a.rowPtr[p]; idx < a.rowPtr[p+1]; idx++ {
+			if col := inv[a.colIndices[idx]]; col < first {
+				first = col
+			}
+		}
+		row := make([]float64, i-first+1)
+		for idx := a.rowPtr[p]; idx < a.rowPtr[p+1]; idx++ {
+			if col := inv[a.colIndices[idx]]; col <= i {
+				row[col-first] += a.values[idx]
+			}
+		}
+		for j := first; j < i; j++ {
+			sum := row[j-first]
+			other, otherFirst := factor.rows[j], factor.first[j]
+			start := otherFirst
+			if first > start {
+				start = first
+			}
+			for k := start; k < j; k++ {
+				sum -= row[k-first] * other[k-otherFirst]
+			}
+			row[j-first] = sum / other[len(other)-1]
+		}
+		pivot := row[i-first]
+		for _, x := range row[:i-first] {
+			pivot -= x * x
+		}
+		if !(pivot > 0) {
+			return nil, fmt.Errorf("non-positive pivot %g at row %d", pivot, p)
+		}
+		row[i-first] = math.Sqrt(pivot)
+		factor.first[i] = first
+		factor.rows[i] = row
+	}
+	return factor, nil
+}
+
+// Permutation returns the permutation P for which
+// P*A*P' = L*L'.
+func (s *SparseCholeskyFactor) Permutation() *PermutationTran {
+	return NewPermutationTran(s.perm)
+}
+
+// NonZeros returns the number of entries stored for
+// the factor L.
+func (s *SparseCholeskyFactor) NonZeros() int {
+	var count int
+	for _, row := range s.rows {
+		count += len(row)
+	}
+	return count
+}
+
+// Solve solves A*x = b using forward and back
+// substitution.
+func (s *SparseCholeskyFactor) Solve(b linalg.Vector) linalg.Vector {
+	if len(b) != len(s.perm) {
+		panic("dimension mismatch")
+	}
+	res := make(linalg.Vector, len(b))
+	for i, p := range s.perm {
+		res[i] = b[p]
+	}
+	for i, row := range s.rows {
+		diag := len(row) - 1
+		res[i] -= linalg.Vector(row[:diag]).DotFast(res[s.first[i]:i])
+		res[i] /= row[diag]
+	}
+	for i := len(s.rows) - 1; i >= 0; i-- {
+		row := s.rows[i]
+		diag := len(row) - 1
+		res[i] /= row[diag]
+		res[s.first[i]:i].AddScaled(row[:diag], -res[i])
+	}
+	solution := make(linalg.Vector, len(b))
+	for i, p := range s.perm {
+		solution[p] = res[i]
+	}
+	return solution
+}
+
+// reverseCuthillMcKee computes an ordering of the rows
+// of a which reduces its bandwidth.
+// Component i of the result is the original index of
+// the row which should come i-th.
+//
+// Each connected component is traversed breadth-first
+// from a vertex of minimum degree, visiting neighbors
+// in order of increasing degree.
+func reverseCuthillMcKee(a *SparseCSR) []int {
+	degree := make([]int, a.dim)
+	for i := range degree {
+		degree[i] = a.rowPtr[i+1] - a.rowPtr[i]
+	}
+	byDegree := make([]int, a.dim)
+	for i := range byDegree {
+		byDegree[i] = i
+	}
+	sort.SliceStable(byDegree, func(i, j int) bool {
+		return degree[byDegree[i]] < degree[byDegree[j]]
+	})
+
+	visited := make([]bool, a.dim)
+	order := make([]int, 0, a.dim)
+	for _, start := range byDegree {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		order = append(order, start)
+		for head := len(order) - 1; head < len(order); head++ {
+			row := order[head]
+			neighborsStart := len(order)
+			for idx := a.rowPtr[row]; idx < a.rowPtr[row+1]; idx++ {
+				if col := a.colIndices[idx]; !visited[col] {
+					visited[col] = true
+					order = append(order, col)
+				}
+			}
+			neighbors := order[neighborsStart:]
+			sort.SliceStable(neighbors, func(i, j int) bool {
+				return degree[neighbors[i]] < degree[neighbors[j]]
+			})
+		}
+	}
+
+	for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
@@ -0,0 +1,56 @@
+package conjgrad
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSparseCholesky(t *testing.T) {
+	// A 2D Laplacian with randomly labeled grid points.
+	const side = 8
+	labels := rand.Perm(side * side)
+	builder := NewCOOBuilder(side * side)
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			idx := labels[y*side+x]
+			builder.Add(idx, idx, 4)
+			if x+1 < side {
+				other := labels[y*side+x+1]
+				builder.Add(idx, other, -1)
+				builder.Add(other, idx, -1)
+			}
+			if y+1 < side {
+				other := labels[(y+1)*side+x]
+				builder.Add(idx, other, -1)
+				builder.Add(other, idx, -1)
+			}
+		}
+	}
+	matrix := builder.Build()
+
+	factor, err := SparseCholesky(matrix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := linalg.RandVector(side * side)
+	checkSolution(t, factor.Solve(matrix.Apply(expected)), expected)
+
+	// The ordering should confine the profile to a
+	// band of width roughly side.
+	if nz := factor.NonZeros(); nz > 2*side*side*side {
+		t.Error("too many non-zeros in factor:", nz)
+	}
+
+	perm := factor.Permutation()
+	v := linalg.RandVector(side * side)
+	checkSolution(t, perm.Inverse().Apply(perm.Apply(v)), v)
+}
+
+func TestSparseCholeskyIndefinite(t *testing.T) {
+	matrix := NewSparseCSR(2, []int{0, 0, 1, 1}, []int{0, 1, 0, 1}, []float64{1, 2, 2, 1})
+	if _, err := SparseCholesky(matrix); err == nil {
+		t.Error("expected error for indefinite matrix")
+	}
+}
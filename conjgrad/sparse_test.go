@@ -0,0 +1,61 @@
+package conjgrad
+
+import "testing"
+
+func TestSparseCSR(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	var rows, cols []int
+	var vals []float64
+	add := func(i, j int, v float64) {
+		rows = append(rows, i)
+		cols = append(cols, j)
+		vals = append(vals, v)
+	}
+	// Insert entries out of order and split each diagonal
+	// entry between two duplicate triplets.
+	for i := 4; i >= 0; i-- {
+		for j := 4; j > i; j-- {
+			add(i, j, lt.M.Get(i, j))
+		}
+		add(i, i, lt.M.Get(i, i)/2)
+		add(i, i, lt.M.Get(i, i)/2)
+	}
+	upper := NewSparseCSR(5, rows, cols, vals)
+	if upper.At(3, 1) != 0 || upper.At(1, 3) != lt.M.Get(1, 3) {
+		t.Error("unexpected entries in upper triangle")
+	}
+	if upper.At(2, 2) != lt.M.Get(2, 2) {
+		t.Error("duplicate entries were not summed")
+	}
+	sym := upper.Symmetrize()
+	if sym.NonZeros() != 25 {
+		t.Error("unexpected number of non-zeros:", sym.NonZeros())
+	}
+	checkSolution(t, SolvePrec(sym, nil, b, 1e-8), realSolution)
+}
+
+func TestCOOBuilder(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	builder := NewCOOBuilder(5)
+	for i := 4; i >= 0; i-- {
+		for j := 0; j < 5; j++ {
+			builder.Add(i, j, lt.M.Get(i, j)/3)
+		}
+	}
+	for j := 4; j >= 0; j-- {
+		for i := 0; i < 5; i++ {
+			builder.Add(i, j, lt.M.Get(i, j)*2/3)
+		}
+	}
+	mat := builder.Build()
+	if mat.NonZeros() != 25 {
+		t.Error("unexpected number of non-zeros:", mat.NonZeros())
+	}
+	checkSolution(t, SolvePrec(mat, nil, b, 1e-8), realSolution)
+
+	// Building twice should give the same matrix.
+	again := builder.Build()
+	if again.At(2, 3) != mat.At(2, 3) {
+		t.Error("second build differs")
+	}
+}
@@ -0,0 +1,86 @@
+package conjgrad
+
+import (
+	"fmt"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+type ssorPreconditioner struct {
+	a        rowTran
+	omega    float64
+	diagonal linalg.Vector
+}
+
+// NewSSORPreconditioner creates a Preconditioner which
+// uses symmetric successive over-relaxation with the
+// relaxation factor omega.
+//
+// The matrix M used by SSOR is built from the diagonal
+// and triangular parts of a, so a must expose its
+// entries; it must be a *DenseMatrix, *SparseCSR, or
+// *SymBand.
+// No storage beyond a itself is needed.
+//
+// This panics if omega is not in the open interval
+// (0, 2), if a is a matrix-free LinTran, or if any
+// diagonal entry of a is zero.
+func NewSSORPreconditioner(a LinTran, omega float64) Preconditioner {
+	if !(omega > 0 && omega < 2) {
+		panic(fmt.Sprintf("relaxation factor %f not in (0, 2)", omega))
+	}
+	rt, ok := a.(rowTran)
+	if !ok {
+		panic("SSOR requires a *DenseMatrix, *SparseCSR, or *SymBand")
+	}
+	diagonal, err := rowTranDiagonal(rt)
+	if err != nil {
+		panic(err.Error())
+	}
+	return &ssorPreconditioner{
+		a:        rt,
+		omega:    omega,
+		diagonal: diagonal,
+	}
+}
+
+// ApplyInverse performs a forward and a backward
+// SSOR sweep.
+func (s *ssorPreconditioner) ApplyInverse(r linalg.Vector) linalg.Vector {
+	if len(r) != len(s.diagonal) {
+		panic("dimension mismatch")
+	}
+	// Solve (D/omega + L)y = r, multiply by D/omega,
+	// and solve (D/omega + U)x = z.
+	res := s.lowerSolve(r)
+	for row, d := range s.diagonal {
+		res[row] *= d / s.omega
+	}
+	res = s.upperSolve(res)
+	return res.Scale((2 - s.omega) / s.omega)
+}
+
+// rowTranDiagonal extracts the diagonal of rt, failing
+// if any diagonal entry is zero.
+func rowTranDiagonal(rt rowTran) (linalg.Vector, error) {
+	diagonal := rowTranDiagonalEntries(rt)
+	for row, x := range diagonal {
+		if x == 0 {
+			return nil, fmt.Errorf("zero diagonal entry at index %d", row)
+		}
+	}
+	return diagonal, nil
+}
+
+// rowTranDiagonalEntries extracts the diagonal of rt.
+func rowTranDiagonalEntries(rt rowTran) linalg.Vector {
+	diagonal := make(linalg.Vector, rt.Dim())
+	for row := range diagonal {
+		rt.iterRow(row, func(col int, val float64) {
+			if col == row {
+				diagonal[row] = val
+			}
+		})
+	}
+	return diagonal
+}
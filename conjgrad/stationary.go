@@ -0,0 +1,147 @@
+package conjgrad
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// SolveGaussSeidel solves a*x = b using Gauss-Seidel
+// iteration, which is mostly useful as a smoother or
+// for teaching.
+// It converges for symmetric positive-definite and
+// for strictly diagonally dominant matrices.
+//
+// Each sweep needs the individual entries of a, so a
+// must be a *DenseMatrix, a *SparseCSR, or a *SymBand.
+// An error is returned for any other operator, or if a
+// has a zero on its diagonal.
+//
+// The solve stops when no component of the residual
+// exceeds prec, or after maxIter sweeps if maxIter is
+// positive.
+func SolveGaussSeidel(a LinTran, b linalg.Vector, prec float64,
+	maxIter int) (linalg.Vector, error) {
+	return relaxationSolve(a, b, 1, prec, maxIter, nil)
+}
+
+// SolveGaussSeidelStoppable is like SolveGaussSeidel,
+// but it returns the current solution once cancelChan
+// is closed.
+func SolveGaussSeidelStoppable(a LinTran, b linalg.Vector, prec float64,
+	maxIter int, cancelChan <-chan struct{}) (linalg.Vector, error) {
+	return relaxationSolve(a, b, 1, prec, maxIter, cancelChan)
+}
+
+// SolveSOR solves a*x = b using successive
+// over-relaxation with the relaxation factor omega.
+//
+// With omega = 1, this is just SolveGaussSeidel.
+// A well-chosen omega, often near 1.9 for
+// Poisson-type problems, can make SOR converge much
+// faster than Gauss-Seidel.
+//
+// As with SolveGaussSeidel, a must expose its entries,
+// and an error is returned if it does not.
+// This panics if omega is not in the open interval
+// (0, 2).
+func SolveSOR(a LinTran, b linalg.Vector, omega, prec float64,
+	maxIter int) (linalg.Vector, error) {
+	return SolveSORStoppable(a, b, omega, prec, maxIter, nil)
+}
+
+// SolveSORStoppable is like SolveSOR, but it returns
+// the current solution once cancelChan is closed.
+func SolveSORStoppable(a LinTran, b linalg.Vector, omega, prec float64,
+	maxIter int, cancelChan <-chan struct{}) (linalg.Vector, error) {
+	if !(omega > 0 && omega < 2) {
+		panic(fmt.Sprintf("relaxation factor %f not in (0, 2)", omega))
+	}
+	return relaxationSolve(a, b, omega, prec, maxIter, cancelChan)
+}
+
+func relaxationSolve(a LinTran, b linalg.Vector, omega, prec float64,
+	maxIter int, cancelChan <-chan struct{}) (linalg.Vector, error) {
+	rt, ok := a.(rowTran)
+	if !ok {
+		return nil, errors.New("operator does not expose its entries")
+	}
+	if len(b) != rt.Dim() {
+		panic("dimension mismatch")
+	}
+	diagonal, err := rowTranDiagonal(rt)
+	if err != nil {
+		return nil, err
+	}
+
+	solution := make(linalg.Vector, len(b))
+	residual := b.Copy()
+	for iters := 0; residual.NormInf() > prec; iters++ {
+		if stopIteration(iters, maxIter, cancelChan) {
+			break
+		}
+		for row := range solution {
+			sum := b[row]
+			rt.iterRow(row, func(col int, val float64) {
+				if col != row {
+					sum -= val * solution[col]
+				}
+			})
+			solution[row] += omega * (sum/diagonal[row] - solution[row])
+		}
+		copy(residual, b)
+		residual.AddScaled(rt.Apply(solution), -1)
+	}
+
+	return solution, nil
+}
+
+// SolveRichardson solves t*x = b using Richardson
+// iteration, x <- x + step*(b - t*x).
+//
+// Only t.Apply is used, so t may be matrix-free.
+// For an SPD t, the iteration converges when step is
+// between 0 and 2/lambdaMax, where lambdaMax is the
+// largest eigenvalue of t; with a larger step, it
+// diverges.
+//
+// The solve stops when no component of the residual
+// exceeds prec, or after maxIter iterations if maxIter
+// is positive.
+func SolveRichardson(t LinTran, b linalg.Vector, step, prec float64,
+	maxIter int) linalg.Vector {
+	return SolveRichardsonStoppable(t, b, step, prec, maxIter, nil)
+}
+
+// SolveRichardsonStoppable is like SolveRichardson,
+// but it returns the current solution once cancelChan
+// is closed.
+func SolveRichardsonStoppable(t LinTran, b linalg.Vector, step, prec float64,
+	maxIter int, cancelChan <-chan struct{}) linalg.Vector {
+	solution := make(linalg.Vector, t.Dim())
+	residual := b.Copy()
+	for iters := 0; residual.NormInf() > prec; iters++ {
+		if stopIteration(iters, maxIter, cancelChan) {
+			break
+		}
+		solution.AddScaled(residual, step)
+		copy(residual, b)
+		residual.AddScaled(t.Apply(solution), -1)
+	}
+	return solution
+}
+
+// SolveRichardsonOptimal is like SolveRichardson, but
+// it uses the step 2/(lambdaMin+lambdaMax), which is
+// optimal when the eigenvalues of t lie in
+// [lambdaMin, lambdaMax].
+//
+// This panics unless 0 < lambdaMin <= lambdaMax.
+func SolveRichardsonOptimal(t LinTran, b linalg.Vector, lambdaMin, lambdaMax,
+	prec float64, maxIter int) linalg.Vector {
+	if !(lambdaMin > 0 && lambdaMin <= lambdaMax) {
+		panic("invalid eigenvalue bounds")
+	}
+	return SolveRichardson(t, b, 2/(lambdaMin+lambdaMax), prec, maxIter)
+}
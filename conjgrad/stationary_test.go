@@ -0,0 +1,69 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveGaussSeidel(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	dense := &DenseMatrix{Rows: 5, Cols: 5, Data: lt.M.Data}
+	solution, err := SolveGaussSeidel(dense, b, 1e-9, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, solution, realSolution)
+
+	if _, err := SolveGaussSeidel(lt, b, 1e-9, 0); err == nil {
+		t.Error("expected error for opaque operator")
+	}
+
+	zeroDiag := &DenseMatrix{Rows: 2, Cols: 2, Data: []float64{0, 1, 1, 0}}
+	if _, err := SolveGaussSeidel(zeroDiag, linalg.Vector{1, 1}, 1e-9, 0); err == nil {
+		t.Error("expected error for zero diagonal")
+	}
+}
+
+func TestSolveSOR(t *testing.T) {
+	const dim = 30
+	band := NewSymBand(dim, 1)
+	for i := 0; i < dim; i++ {
+		band.SetBand(i, 0, 2)
+		if i+1 < dim {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	expected := linalg.RandVector(dim)
+	b := band.Apply(expected)
+
+	solution, err := SolveSOR(band, b, 1.8, 1e-9, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSolution(t, solution, expected)
+
+	// Over-relaxation should beat Gauss-Seidel here.
+	sorSolution, _ := SolveSOR(band, b, 1.8, 0, 100)
+	gsSolution, _ := SolveGaussSeidel(band, b, 0, 100)
+	sorErr := sorSolution.Copy().Sub(expected).NormInf()
+	gsErr := gsSolution.Copy().Sub(expected).NormInf()
+	if sorErr >= gsErr {
+		t.Errorf("SOR error %g is not below Gauss-Seidel error %g", sorErr, gsErr)
+	}
+}
+
+func TestSolveRichardson(t *testing.T) {
+	diag := Diagonal(linalg.Vector{1, 2, 3, 4})
+	b := linalg.Vector{1, 1, 1, 1}
+	expected := linalg.Vector{1, 0.5, 1.0 / 3, 0.25}
+
+	checkSolution(t, SolveRichardson(diag, b, 0.3, 1e-10, 0), expected)
+	checkSolution(t, SolveRichardsonOptimal(diag, b, 1, 4, 1e-10, 0), expected)
+
+	// A step beyond 2/lambdaMax diverges.
+	diverged := SolveRichardson(diag, b, 0.6, 1e-10, 50)
+	if diverged.Copy().Sub(expected).NormInf() < 1 {
+		t.Error("expected divergence with a large step")
+	}
+}
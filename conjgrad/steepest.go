@@ -0,0 +1,71 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// SolveSteepestDescent solves t*x = b for a symmetric
+// positive-definite t using steepest descent with an
+// exact line search.
+//
+// Each step moves along the residual, which is the
+// negative gradient of x'*t*x/2 - b'*x.
+// This usually converges far more slowly than CG, and
+// is mostly useful for comparison.
+//
+// The solve stops when no component of the residual
+// exceeds prec, or after maxIter iterations if maxIter
+// is positive.
+func SolveSteepestDescent(t LinTran, b linalg.Vector, prec float64,
+	maxIter int) linalg.Vector {
+	return steepestDescent(t, b, prec, maxIter, nil, nil)
+}
+
+// SolveSteepestDescentStoppable is like
+// SolveSteepestDescent, but it returns the current
+// solution once cancelChan is closed.
+func SolveSteepestDescentStoppable(t LinTran, b linalg.Vector, prec float64,
+	maxIter int, cancelChan <-chan struct{}) linalg.Vector {
+	return steepestDescent(t, b, prec, maxIter, nil, cancelChan)
+}
+
+// SolveSteepestDescentWithHistory is like
+// SolveSteepestDescent, but it also returns the 2-norm
+// of the residual after each iteration, just like
+// SolveWithHistory does for CG.
+func SolveSteepestDescentWithHistory(t LinTran, b linalg.Vector, prec float64,
+	maxIter int) (linalg.Vector, []float64) {
+	var history []float64
+	solution := steepestDescent(t, b, prec, maxIter, &history, nil)
+	return solution, history
+}
+
+func steepestDescent(t LinTran, b linalg.Vector, prec float64, maxIter int,
+	history *[]float64, cancelChan <-chan struct{}) linalg.Vector {
+	residual := b.Copy()
+	solution := make(linalg.Vector, t.Dim())
+
+	for iters := 0; residual.NormInf() > prec; iters++ {
+		if maxIter > 0 && iters >= maxIter {
+			break
+		}
+		appliedResidual := t.Apply(residual)
+		curvature := residual.Dot(appliedResidual)
+		if curvature == 0 {
+			break
+		}
+		step := residual.Dot(residual) / curvature
+		solution.AddScaled(residual, step)
+
+		updateResidual(t, b, solution, residual, appliedResidual, step,
+			iters, residualUpdateFrequency, nil)
+
+		if history != nil {
+			*history = append(*history, residual.Mag())
+		}
+
+		if cancelled(cancelChan) {
+			break
+		}
+	}
+
+	return solution
+}
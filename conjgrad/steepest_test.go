@@ -0,0 +1,22 @@
+package conjgrad
+
+import "testing"
+
+func TestSolveSteepestDescent(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution := SolveSteepestDescent(lt, b, 1e-10, 0)
+	checkSolution(t, solution, realSolution)
+
+	solution, history := SolveSteepestDescentWithHistory(lt, b, 1e-10, 0)
+	checkSolution(t, solution, realSolution)
+	_, cgHistory := SolveWithHistory(lt, b, 1e-10)
+	if len(history) <= len(cgHistory) {
+		t.Errorf("steepest descent took %d iterations, CG took %d",
+			len(history), len(cgHistory))
+	}
+
+	_, history = SolveSteepestDescentWithHistory(lt, b, 1e-10, 3)
+	if len(history) != 3 {
+		t.Errorf("expected 3 iterations but got %d", len(history))
+	}
+}
@@ -0,0 +1,63 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// progressBuffer is the number of Progress values
+// which SolveStreaming buffers for a slow consumer.
+const progressBuffer = 16
+
+// Progress describes the state of a solve after an
+// iteration.
+type Progress struct {
+	// Iteration is the number of iterations completed.
+	Iteration int
+
+	// ResidualNorm is the largest absolute value of
+	// any component of the residual.
+	ResidualNorm float64
+}
+
+// SolveStreaming is like Solve, but it runs the solve
+// in the background and reports its progress on the
+// returned channel.
+//
+// The returned vector is filled in with the solution
+// once the solve finishes, at which point the channel
+// is closed.
+// Thus, the vector must not be used until the channel
+// has been drained.
+//
+// Progress values are sent without blocking, and only
+// a few of them are buffered.
+// If the consumer falls behind, updates are dropped,
+// so the consumer may not see every iteration.
+func SolveStreaming(t LinTran, b linalg.Vector, prec float64) (linalg.Vector,
+	<-chan Progress) {
+	return SolveStreamingStoppable(t, b, prec, nil)
+}
+
+// SolveStreamingStoppable is like SolveStreaming, but
+// the solve is stopped once cancelChan is closed.
+// The channel is still closed, and the vector holds
+// the approximate solution found so far.
+func SolveStreamingStoppable(t LinTran, b linalg.Vector, prec float64,
+	cancelChan <-chan struct{}) (linalg.Vector, <-chan Progress) {
+	solution := make(linalg.Vector, t.Dim())
+	progress := make(chan Progress, progressBuffer)
+	go func() {
+		defer close(progress)
+		res := solve(t, b, &SolveOptions{
+			Tolerance: prec,
+			Cancel:    cancelChan,
+			Observe: func(iter int, residualNorm float64) bool {
+				select {
+				case progress <- Progress{Iteration: iter, ResidualNorm: residualNorm}:
+				default:
+				}
+				return true
+			},
+		})
+		copy(solution, res.Solution)
+	}()
+	return solution, progress
+}
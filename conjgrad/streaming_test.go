@@ -0,0 +1,28 @@
+package conjgrad
+
+import "testing"
+
+func TestSolveStreaming(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution, progress := SolveStreaming(lt, b, 1e-8)
+	lastIter := 0
+	for p := range progress {
+		if p.Iteration <= lastIter {
+			t.Errorf("iteration %d came after %d", p.Iteration, lastIter)
+		}
+		lastIter = p.Iteration
+	}
+	if lastIter == 0 {
+		t.Error("no progress was reported")
+	}
+	checkSolution(t, solution, realSolution)
+
+	cancelChan := make(chan struct{})
+	close(cancelChan)
+	solution, progress = SolveStreamingStoppable(lt, b, -1, cancelChan)
+	for range progress {
+	}
+	if len(solution) != len(b) {
+		t.Error("bad solution length", len(solution))
+	}
+}
@@ -0,0 +1,94 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// SymBand is a symmetric banded matrix which stores
+// only its diagonal and the superdiagonals within a
+// given half-bandwidth.
+type SymBand struct {
+	dim int
+
+	// bands[d][i] is the entry at row i, column i+d.
+	bands [][]float64
+}
+
+// NewSymBand creates a zero SymBand with the given
+// dimension and half-bandwidth.
+// A half-bandwidth of 1 gives a tridiagonal matrix.
+func NewSymBand(dim, halfBandwidth int) *SymBand {
+	if dim <= 0 || halfBandwidth < 0 {
+		panic("invalid band dimensions")
+	}
+	if halfBandwidth >= dim {
+		halfBandwidth = dim - 1
+	}
+	bands := make([][]float64, halfBandwidth+1)
+	for d := range bands {
+		bands[d] = make([]float64, dim-d)
+	}
+	return &SymBand{dim: dim, bands: bands}
+}
+
+// Dim returns the dimension of the matrix.
+func (s *SymBand) Dim() int {
+	return s.dim
+}
+
+// HalfBandwidth returns the number of stored
+// superdiagonals.
+func (s *SymBand) HalfBandwidth() int {
+	return len(s.bands) - 1
+}
+
+// Band returns the entry at row i, column i+diag,
+// which is also the entry at row i+diag, column i.
+func (s *SymBand) Band(i, diag int) float64 {
+	return s.bands[diag][i]
+}
+
+// SetBand sets the entry at row i, column i+diag,
+// along with its mirror at row i+diag, column i.
+func (s *SymBand) SetBand(i, diag int, v float64) {
+	s.bands[diag][i] = v
+}
+
+// Apply multiplies the matrix by v in O(dim*k) time,
+// where k is the half-bandwidth.
+func (s *SymBand) Apply(v linalg.Vector) linalg.Vector {
+	res := make(linalg.Vector, s.dim)
+	s.ApplyInto(res, v)
+	return res
+}
+
+// ApplyInto stores the product of the matrix and src
+// in dst.
+func (s *SymBand) ApplyInto(dst, src linalg.Vector) {
+	if len(src) != s.dim || len(dst) != s.dim {
+		panic("dimension mismatch")
+	}
+	for i, x := range s.bands[0] {
+		dst[i] = x * src[i]
+	}
+	for d := 1; d < len(s.bands); d++ {
+		band := s.bands[d]
+		upper := src[d:]
+		lower := dst[d:]
+		for i, x := range band {
+			dst[i] += x * upper[i]
+			lower[i] += x * src[i]
+		}
+	}
+}
+
+func (s *SymBand) iterRow(row int, f func(col int, val float64)) {
+	for d := len(s.bands) - 1; d > 0; d-- {
+		if row-d >= 0 {
+			f(row-d, s.bands[d][row-d])
+		}
+	}
+	for d, band := range s.bands {
+		if row < len(band) {
+			f(row+d, band[row])
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package conjgrad
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSymBandApply(t *testing.T) {
+	const dim = 7
+	band := NewSymBand(dim, 2)
+	dense := NewDenseMatrix(dim, dim)
+	for d := 0; d <= 2; d++ {
+		for i := 0; i+d < dim; i++ {
+			v := rand.NormFloat64()
+			band.SetBand(i, d, v)
+			dense.Set(i, i+d, v)
+			dense.Set(i+d, i, v)
+		}
+	}
+
+	vec := make(linalg.Vector, dim)
+	for i := range vec {
+		vec[i] = rand.NormFloat64()
+	}
+	checkSolution(t, band.Apply(vec), dense.Apply(vec))
+
+	for row := 0; row < dim; row++ {
+		expected := make(linalg.Vector, dim)
+		actual := make(linalg.Vector, dim)
+		dense.iterRow(row, func(col int, val float64) {
+			expected[col] = val
+		})
+		band.iterRow(row, func(col int, val float64) {
+			actual[col] = val
+		})
+		checkSolution(t, actual, expected)
+	}
+}
+
+func TestSymBandSolve(t *testing.T) {
+	// The 1D Laplacian is tridiagonal.
+	const dim = 50
+	band := NewSymBand(dim, 1)
+	for i := 0; i < dim; i++ {
+		band.SetBand(i, 0, 2)
+		if i+1 < dim {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	expected := make(linalg.Vector, dim)
+	for i := range expected {
+		expected[i] = rand.NormFloat64()
+	}
+	solution := SolvePrec(band, nil, band.Apply(expected), 1e-10)
+	checkSolution(t, solution, expected)
+}
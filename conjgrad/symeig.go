@@ -0,0 +1,176 @@
+package conjgrad
+
+import (
+	"math"
+	"sort"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// jacobiSweeps is the maximum number of sweeps used
+// by the dense Jacobi eigenvalue solver.
+const jacobiSweeps = 100
+
+// SymEig approximates numEigs eigenpairs at one end of
+// the spectrum of a symmetric operator t.
+//
+// It runs up to maxSteps steps of Lanczos from a random
+// start, solves the small tridiagonal eigenproblem,
+// and maps the Ritz vectors back to the full space.
+// If largest is true, the largest eigenvalues are
+// returned in descending order; otherwise, the
+// smallest are returned in ascending order.
+//
+// Every Lanczos vector is reorthogonalized against all
+// of the previous ones, which prevents spurious copies
+// of converged eigenvalues but costs O(n*steps^2) time
+// and n*steps memory.
+// The results are only approximate, and they improve
+// as maxSteps grows.
+func SymEig(t LinTran, numEigs, maxSteps int,
+	largest bool) (values linalg.Vector, vectors []linalg.Vector) {
+	return SymEigFrom(t, linalg.RandVector(t.Dim()), numEigs, maxSteps, largest)
+}
+
+// SymEigFrom is like SymEig, but Lanczos starts from
+// the given vector instead of a random one.
+// A start close to the wanted eigenvectors, such as a
+// result from a previous call, speeds up convergence.
+//
+// The start is normalized automatically.
+// This panics if it has the wrong length or is zero.
+func SymEigFrom(t LinTran, start linalg.Vector, numEigs, maxSteps int,
+	largest bool) (values linalg.Vector, vectors []linalg.Vector) {
+	checkStart(t, start)
+	if maxSteps > t.Dim() {
+		maxSteps = t.Dim()
+	}
+	basis, tridiag := lanczosReorthogonalized(t, start, maxSteps)
+	ritzValues, ritzVectors := denseSymEigen(tridiag)
+
+	if numEigs > len(ritzValues) {
+		numEigs = len(ritzValues)
+	}
+	for i := 0; i < numEigs; i++ {
+		idx := i
+		if largest {
+			idx = len(ritzValues) - 1 - i
+		}
+		values = append(values, ritzValues[idx])
+		vec := make(linalg.Vector, t.Dim())
+		for j, q := range basis {
+			vec.AddScaled(q, ritzVectors[idx][j])
+		}
+		vectors = append(vectors, vec.Normalize())
+	}
+	return
+}
+
+// lanczosReorthogonalized is like Lanczos, but it
+// keeps the basis vectors and orthogonalizes each new
+// one against all of them.
+// It returns the basis and the tridiagonal matrix as a
+// dense matrix.
+func lanczosReorthogonalized(t LinTran, start linalg.Vector,
+	steps int) ([]linalg.Vector, *DenseMatrix) {
+	basis := []linalg.Vector{start.Unit()}
+	var alphas, betas linalg.Vector
+	var scale float64
+	for i := 0; i < steps; i++ {
+		next := t.Apply(basis[i])
+		alpha := next.Dot(basis[i])
+		alphas = append(alphas, alpha)
+		scale = math.Max(scale, math.Abs(alpha))
+		if i == steps-1 {
+			break
+		}
+		projectOut(next, basis)
+		beta := next.Mag()
+		if beta <= lanczosBreakdown*math.Max(scale, beta) || beta == 0 {
+			break
+		}
+		scale = math.Max(scale, beta)
+		betas = append(betas, beta)
+		basis = append(basis, next.Scale(1/beta))
+	}
+
+	tridiag := NewDenseMatrix(len(alphas), len(alphas))
+	for i, a := range alphas {
+		tridiag.Set(i, i, a)
+		if i < len(betas) {
+			tridiag.SetSym(i, i+1, betas[i])
+		}
+	}
+	return basis[:len(alphas)], tridiag
+}
+
+// denseSymEigen computes the eigenvalues (ascending)
+// and unit eigenvectors of a small dense symmetric
+// matrix using the cyclic Jacobi method.
+func denseSymEigen(m *DenseMatrix) (linalg.Vector, []linalg.Vector) {
+	n := m.Rows
+	a := &DenseMatrix{Rows: n, Cols: n, Data: append([]float64{}, m.Data...)}
+	vecs := NewDenseMatrix(n, n)
+	for i := 0; i < n; i++ {
+		vecs.Set(i, i, 1)
+	}
+
+	for sweep := 0; sweep < jacobiSweeps; sweep++ {
+		var offDiag, total float64
+		for i, x := range a.Data {
+			total += x * x
+			if i/n != i%n {
+				offDiag += x * x
+			}
+		}
+		if offDiag <= 1e-30*total {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				apq := a.At(p, q)
+				if apq == 0 {
+					continue
+				}
+				// Choose the rotation which zeroes a[p][q].
+				theta := (a.At(q, q) - a.At(p, p)) / (2 * apq)
+				tan := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(tan*tan+1)
+				s := tan * c
+				for k := 0; k < n; k++ {
+					akp, akq := a.At(k, p), a.At(k, q)
+					a.Set(k, p, c*akp-s*akq)
+					a.Set(k, q, s*akp+c*akq)
+				}
+				for k := 0; k < n; k++ {
+					apk, aqk := a.At(p, k), a.At(q, k)
+					a.Set(p, k, c*apk-s*aqk)
+					a.Set(q, k, s*apk+c*aqk)
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := vecs.At(k, p), vecs.At(k, q)
+					vecs.Set(k, p, c*vkp-s*vkq)
+					vecs.Set(k, q, s*vkp+c*vkq)
+				}
+			}
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return a.At(order[i], order[i]) < a.At(order[j], order[j])
+	})
+	values := make(linalg.Vector, n)
+	vectors := make([]linalg.Vector, n)
+	for i, idx := range order {
+		values[i] = a.At(idx, idx)
+		vectors[i] = make(linalg.Vector, n)
+		for k := range vectors[i] {
+			vectors[i][k] = vecs.At(k, idx)
+		}
+	}
+	return values, vectors
+}
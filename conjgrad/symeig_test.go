@@ -0,0 +1,78 @@
+package conjgrad
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSymEig(t *testing.T) {
+	const dim = 30
+	band := NewSymBand(dim, 1)
+	for i := 0; i < dim; i++ {
+		band.SetBand(i, 0, 2)
+		if i+1 < dim {
+			band.SetBand(i, 1, -1)
+		}
+	}
+	exact := func(k int) float64 {
+		return 2 - 2*math.Cos(float64(k)*math.Pi/(dim+1))
+	}
+
+	values, vectors := SymEig(band, 3, dim, true)
+	values1, vectors1 := SymEig(band, 3, dim, false)
+	for i := 0; i < 3; i++ {
+		if math.Abs(values[i]-exact(dim-i)) > 1e-8 {
+			t.Errorf("largest %d: expected %f but got %f", i, exact(dim-i), values[i])
+		}
+		if math.Abs(values1[i]-exact(i+1)) > 1e-8 {
+			t.Errorf("smallest %d: expected %f but got %f", i, exact(i+1), values1[i])
+		}
+		for _, pair := range []struct {
+			val float64
+			vec linalg.Vector
+		}{{values[i], vectors[i]}, {values1[i], vectors1[i]}} {
+			residual := band.Apply(pair.vec).AddScaled(pair.vec, -pair.val)
+			if residual.Norm() > 1e-6 {
+				t.Errorf("eigenvector residual too large: %g", residual.Norm())
+			}
+		}
+	}
+}
+
+func TestDenseSymEigen(t *testing.T) {
+	lt, _, _ := testProblem()
+	dense := &DenseMatrix{Rows: 5, Cols: 5, Data: lt.M.Data}
+	values, vectors := denseSymEigen(dense)
+	for i, val := range values {
+		if i > 0 && val < values[i-1] {
+			t.Error("eigenvalues are not sorted")
+		}
+		residual := dense.Apply(vectors[i]).AddScaled(vectors[i], -val)
+		if residual.Norm() > 1e-10 {
+			t.Errorf("eigenpair %d residual is %g", i, residual.Norm())
+		}
+	}
+}
+
+func TestSymEigFrom(t *testing.T) {
+	diag := make(Diagonal, 30)
+	for i := range diag {
+		diag[i] = float64(i + 1)
+	}
+	// A start near the first eigenvector finds the
+	// smallest eigenvalue in very few steps.
+	start := make(linalg.Vector, 30)
+	for i := range start {
+		start[i] = 1e-4
+	}
+	start[0] = 1
+	values, vectors := SymEigFrom(diag, start, 1, 2, false)
+	if math.Abs(values[0]-1) > 1e-6 {
+		t.Error("expected eigenvalue 1 but got", values[0])
+	}
+	if math.Abs(math.Abs(vectors[0][0])-1) > 1e-6 {
+		t.Error("unexpected eigenvector", vectors[0])
+	}
+}
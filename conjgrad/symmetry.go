@@ -0,0 +1,81 @@
+package conjgrad
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// symmetrySeed seeds the random vectors used by
+// CheckSymmetric, so that checks are reproducible.
+const symmetrySeed = 1337
+
+// CheckSymmetric does a randomized check that t is
+// symmetric.
+//
+// It draws samples pairs of random unit vectors u and v
+// and checks that <t*u, v> and <u, t*v> differ by at
+// most tol.
+// If they differ by more than tol for any pair, an
+// error describing the largest difference is returned.
+//
+// The random vectors come from a fixed seed, so the
+// result is the same every time for a given t.
+func CheckSymmetric(t LinTran, samples int, tol float64) error {
+	gen := rand.New(rand.NewSource(symmetrySeed))
+	var worst float64
+	var worstSample int
+	for i := 0; i < samples; i++ {
+		u := randomUnitVector(gen, t.Dim())
+		v := randomUnitVector(gen, t.Dim())
+		diff := math.Abs(t.Apply(u).Dot(v) - u.Dot(t.Apply(v)))
+		if diff > worst || math.IsNaN(diff) {
+			worst = diff
+			worstSample = i
+		}
+	}
+	if worst > tol || math.IsNaN(worst) {
+		return fmt.Errorf("operator is not symmetric: <Au,v> and <u,Av> differ by %g "+
+			"(sample %d)", worst, worstSample)
+	}
+	return nil
+}
+
+func randomUnitVector(gen *rand.Rand, dim int) linalg.Vector {
+	res := RandomVector(dim, gen)
+	return res.Scale(1 / res.Mag())
+}
+
+// Symmetrized returns the symmetric part of t, i.e.
+// the operator (t + t')/2.
+//
+// This makes an operator which is symmetric only up
+// to rounding error safe to use with CG.
+// Each Apply calls both t.Apply and t.ApplyTranspose,
+// so it costs twice as much as applying t.
+//
+// This is a workaround rather than a fix: if t is far
+// from symmetric, the symmetric part may have little
+// to do with the system t*x = b.
+func Symmetrized(t TransposableLinTran) LinTran {
+	return symmetrizedTran{t}
+}
+
+type symmetrizedTran struct {
+	t TransposableLinTran
+}
+
+func (s symmetrizedTran) Dim() int {
+	return s.t.Dim()
+}
+
+func (s symmetrizedTran) Apply(v linalg.Vector) linalg.Vector {
+	res := s.t.Apply(v).Copy()
+	return res.Add(s.t.ApplyTranspose(v)).Scale(0.5)
+}
+
+func (s symmetrizedTran) ApplyTranspose(v linalg.Vector) linalg.Vector {
+	return s.Apply(v)
+}
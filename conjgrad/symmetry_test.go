@@ -0,0 +1,56 @@
+package conjgrad
+
+import (
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestCheckSymmetric(t *testing.T) {
+	lt, _, _ := testProblem()
+	if err := CheckSymmetric(lt, 10, 1e-10); err != nil {
+		t.Error(err)
+	}
+	nonSym, _, _ := nonSymmetricProblem()
+	if err := CheckSymmetric(nonSym, 10, 1e-10); err == nil {
+		t.Error("expected error for non-symmetric operator")
+	}
+}
+
+func TestSymmetrized(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	perturbed := *lt.M
+	perturbed.Data = append([]float64{}, lt.M.Data...)
+	perturbed.Set(0, 1, perturbed.Get(0, 1)+1e-6)
+	nearlySym := MatLinTran{M: &perturbed}
+	if err := CheckSymmetric(nearlySym, 10, 1e-10); err == nil {
+		t.Fatal("expected error for perturbed operator")
+	}
+	sym := Symmetrized(nearlySym)
+	if err := CheckSymmetric(sym, 10, 1e-10); err != nil {
+		t.Error(err)
+	}
+	checkSolution(t, Solve(Symmetrized(lt), b, 1e-8), realSolution)
+}
+
+// aliasingTran is the identity operator, except that
+// its transpose is doubled. Apply returns its argument.
+type aliasingTran int
+
+func (a aliasingTran) Dim() int {
+	return int(a)
+}
+
+func (a aliasingTran) Apply(v linalg.Vector) linalg.Vector {
+	return v
+}
+
+func (a aliasingTran) ApplyTranspose(v linalg.Vector) linalg.Vector {
+	return v.Copy().Scale(2)
+}
+
+func TestSymmetrizedAliasing(t *testing.T) {
+	v := linalg.Vector{1, 2}
+	checkSolution(t, Symmetrized(aliasingTran(2)).Apply(v), linalg.Vector{1.5, 3})
+	checkSolution(t, v, linalg.Vector{1, 2})
+}
@@ -0,0 +1,61 @@
+package conjgrad
+
+import (
+	"math/rand"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// TraceEstimate estimates the trace of t using
+// Hutchinson's method, which averages z'*t*z over
+// random probe vectors z.
+//
+// The probes have independent entries of +1 or -1.
+// Among probes with independent entries, these
+// Rademacher probes give the smallest variance.
+// The variance decreases like 1/probes.
+// If gen is nil, the global random source is used.
+func TraceEstimate(t LinTran, probes int, gen *rand.Rand) float64 {
+	return hutchinson(t.Dim(), t.Apply, probes, gen)
+}
+
+// TraceInverseEstimate is like TraceEstimate, but
+// it estimates the trace of A^-1 for an operator A of
+// dimension dim, where solve(b) returns A^-1*b.
+//
+// For example, solve could wrap one of the CG solvers.
+// Inexact solves add to the error of the estimate.
+func TraceInverseEstimate(dim int, solve func(linalg.Vector) linalg.Vector, probes int,
+	gen *rand.Rand) float64 {
+	return hutchinson(dim, solve, probes, gen)
+}
+
+func hutchinson(dim int, apply func(linalg.Vector) linalg.Vector, probes int,
+	gen *rand.Rand) float64 {
+	if probes <= 0 {
+		panic("probes must be positive")
+	}
+	var sum float64
+	for i := 0; i < probes; i++ {
+		probe := rademacherVector(gen, dim)
+		sum += probe.Dot(apply(probe))
+	}
+	return sum / float64(probes)
+}
+
+// rademacherVector creates a vector whose entries are
+// independently +1 or -1 with equal probability.
+// If gen is nil, the global random source is used.
+func rademacherVector(gen *rand.Rand, dim int) linalg.Vector {
+	res := make(linalg.Vector, dim)
+	for i := range res {
+		var bit int64
+		if gen != nil {
+			bit = gen.Int63() & 1
+		} else {
+			bit = rand.Int63() & 1
+		}
+		res[i] = float64(bit*2 - 1)
+	}
+	return res
+}
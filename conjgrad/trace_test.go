@@ -0,0 +1,44 @@
+package conjgrad
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestTraceEstimate(t *testing.T) {
+	// For a diagonal matrix, every Rademacher probe
+	// gives the exact trace.
+	diag := Diagonal{1, 2, 3, 4}
+	if tr := TraceEstimate(diag, 3, nil); math.Abs(tr-10) > 1e-12 {
+		t.Error("expected trace 10 but got", tr)
+	}
+	inv := TraceInverseEstimate(4, diag.Inverse().Apply, 3, nil)
+	if math.Abs(inv-25.0/12) > 1e-12 {
+		t.Error("expected inverse trace 25/12 but got", inv)
+	}
+
+	lt, _, _ := testProblem()
+	var expected float64
+	for i := 0; i < 5; i++ {
+		expected += lt.M.Get(i, i)
+	}
+	gen := rand.New(rand.NewSource(1))
+	if tr := TraceEstimate(lt, 2000, gen); math.Abs(tr-expected) > 0.05*expected {
+		t.Errorf("expected trace near %f but got %f", expected, tr)
+	}
+
+	solve := func(b linalg.Vector) linalg.Vector {
+		return Solve(lt, b, 0)
+	}
+	inverse := Inverse(lt, 0)
+	expected = 0
+	for i := 0; i < 5; i++ {
+		expected += inverse.At(i, i)
+	}
+	if tr := TraceInverseEstimate(5, solve, 2000, gen); math.Abs(tr-expected) > 0.05*expected {
+		t.Errorf("expected inverse trace near %f but got %f", expected, tr)
+	}
+}
@@ -0,0 +1,44 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// SolveTridiagonal solves a tridiagonal system exactly
+// in O(n) time using the Thomas algorithm.
+//
+// The diag argument is the main diagonal, sub is the
+// diagonal below it, and super is the diagonal above.
+// No pivoting is done, which is stable for diagonally
+// dominant or symmetric positive-definite systems.
+//
+// This panics if a zero pivot is encountered.
+func SolveTridiagonal(sub, diag, super, b linalg.Vector) linalg.Vector {
+	n := len(diag)
+	if len(b) != n || len(sub) != n-1 || len(super) != n-1 {
+		panic("dimension mismatch")
+	}
+	if n == 0 {
+		return linalg.Vector{}
+	}
+
+	upper := make(linalg.Vector, n-1)
+	solution := make(linalg.Vector, n)
+
+	pivot := diag[0]
+	if pivot == 0 {
+		panic("zero pivot")
+	}
+	solution[0] = b[0] / pivot
+	for i := 1; i < n; i++ {
+		upper[i-1] = super[i-1] / pivot
+		pivot = diag[i] - sub[i-1]*upper[i-1]
+		if pivot == 0 {
+			panic("zero pivot")
+		}
+		solution[i] = (b[i] - sub[i-1]*solution[i-1]) / pivot
+	}
+	for i := n - 2; i >= 0; i-- {
+		solution[i] -= upper[i] * solution[i+1]
+	}
+
+	return solution
+}
@@ -0,0 +1,109 @@
+package conjgrad
+
+import (
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// tridiagEigenvalues computes the eigenvalues of a
+// symmetric tridiagonal matrix using bisection on
+// Sturm sequences.
+// The eigenvalues are returned in ascending order.
+func tridiagEigenvalues(alphas, betas linalg.Vector) linalg.Vector {
+	if len(alphas) == 0 {
+		return nil
+	}
+
+	// Gershgorin discs bound the whole spectrum.
+	low, high := math.Inf(1), math.Inf(-1)
+	for i, a := range alphas {
+		var radius float64
+		if i > 0 {
+			radius += math.Abs(betas[i-1])
+		}
+		if i < len(betas) {
+			radius += math.Abs(betas[i])
+		}
+		low = math.Min(low, a-radius)
+		high = math.Max(high, a+radius)
+	}
+
+	res := make(linalg.Vector, len(alphas))
+	for k := range res {
+		lo, hi := low, high
+		for i := 0; i < 200; i++ {
+			mid := (lo + hi) / 2
+			if mid <= lo || mid >= hi {
+				break
+			}
+			if sturmCount(alphas, betas, mid) > k {
+				hi = mid
+			} else {
+				lo = mid
+			}
+		}
+		res[k] = (lo + hi) / 2
+	}
+	return res
+}
+
+// sturmCount returns the number of eigenvalues of a
+// symmetric tridiagonal matrix which are less than x.
+func sturmCount(alphas, betas linalg.Vector, x float64) int {
+	var count int
+	var d float64
+	for i, a := range alphas {
+		if i == 0 {
+			d = a - x
+		} else {
+			d = a - x - betas[i-1]*betas[i-1]/d
+		}
+		if d == 0 {
+			d = -math.SmallestNonzeroFloat64
+		}
+		if d < 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// tridiagFirstWeights computes the squared first
+// component of each unit eigenvector of a symmetric
+// tridiagonal matrix, given its eigenvalues.
+//
+// It uses the identity that the weight for eigenvalue
+// x is det(T2-x*I) / prod_{j!=k}(x-eigs[j]), where T2
+// is T without its first row and column.
+// Both products are accumulated as logarithms to
+// avoid overflow.
+func tridiagFirstWeights(alphas, betas, eigs linalg.Vector) linalg.Vector {
+	res := make(linalg.Vector, len(eigs))
+	if len(eigs) == 1 {
+		res[0] = 1
+		return res
+	}
+	for k, x := range eigs {
+		var logWeight float64
+		var d float64
+		for i := 1; i < len(alphas); i++ {
+			if i == 1 {
+				d = alphas[i] - x
+			} else {
+				d = alphas[i] - x - betas[i-1]*betas[i-1]/d
+			}
+			if d == 0 {
+				d = math.SmallestNonzeroFloat64
+			}
+			logWeight += math.Log(math.Abs(d))
+		}
+		for j, y := range eigs {
+			if j != k {
+				logWeight -= math.Log(math.Abs(x - y))
+			}
+		}
+		res[k] = math.Exp(logWeight)
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+package conjgrad
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveTridiagonal(t *testing.T) {
+	const dim = 20
+	sub := make(linalg.Vector, dim-1)
+	diag := make(linalg.Vector, dim)
+	super := make(linalg.Vector, dim-1)
+	dense := NewDenseMatrix(dim, dim)
+	for i := range diag {
+		diag[i] = 4 + rand.Float64()
+		dense.Set(i, i, diag[i])
+		if i+1 < dim {
+			sub[i] = rand.NormFloat64()
+			super[i] = rand.NormFloat64()
+			dense.Set(i+1, i, sub[i])
+			dense.Set(i, i+1, super[i])
+		}
+	}
+
+	expected := make(linalg.Vector, dim)
+	for i := range expected {
+		expected[i] = rand.NormFloat64()
+	}
+	actual := SolveTridiagonal(sub, diag, super, dense.Apply(expected))
+	checkSolution(t, actual, expected)
+}
+
+func TestSolveTridiagonalZeroPivot(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	SolveTridiagonal(linalg.Vector{1}, linalg.Vector{1, 1}, linalg.Vector{1},
+		linalg.Vector{1, 2})
+}
@@ -0,0 +1,78 @@
+package conjgrad
+
+import (
+	"math"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+// trustRegionExtraIters is the number of iterations
+// beyond t.Dim() that SolveTrustRegion allows, since
+// rounding error keeps CG from finishing in exactly
+// t.Dim() steps.
+const trustRegionExtraIters = 10
+
+// SolveTrustRegion approximately minimizes
+// 0.5*x'*t*x - b'*x subject to |x| <= radius using
+// the Steihaug-Toint truncated CG method.
+//
+// The operator t must be symmetric, but it need not
+// be positive-definite.
+// CG runs as usual until no component of the residual
+// exceeds prec.
+// If a step would leave the trust region, or if a
+// direction of non-positive curvature is found, the
+// solve stops at the point where the current search
+// direction meets the boundary.
+//
+// At most t.Dim()+10 iterations are run, after which
+// the current solution is returned.
+// Thus, a prec of 0 gives the most accurate solution
+// that this many steps can reach.
+func SolveTrustRegion(t LinTran, b linalg.Vector, radius, prec float64) linalg.Vector {
+	return SolveTrustRegionStoppable(t, b, radius, prec, nil)
+}
+
+// SolveTrustRegionStoppable is like SolveTrustRegion,
+// but it returns the current solution once cancelChan
+// is closed.
+func SolveTrustRegionStoppable(t LinTran, b linalg.Vector, radius, prec float64,
+	cancelChan <-chan struct{}) linalg.Vector {
+	solution := make(linalg.Vector, t.Dim())
+	residual := b.Copy()
+	conjVec := residual.Copy()
+	residualDot := residual.Dot(residual)
+
+	maxIter := t.Dim() + trustRegionExtraIters
+	for iters := 0; !stopIteration(iters, maxIter, cancelChan) && residual.NormInf() > prec; iters++ {
+		applied := t.Apply(conjVec)
+		curvature := conjVec.Dot(applied)
+		if curvature <= 0 {
+			return solution.AddScaled(conjVec, boundaryStep(solution, conjVec, radius))
+		}
+		step := residualDot / curvature
+		next := solution.Copy().AddScaled(conjVec, step)
+		if next.Norm() >= radius {
+			return solution.AddScaled(conjVec, boundaryStep(solution, conjVec, radius))
+		}
+		solution = next
+		updateResidual(t, b, solution, residual, applied, step,
+			iters, residualUpdateFrequency, nil)
+
+		newResidualDot := residual.Dot(residual)
+		conjVec.Scale(newResidualDot / residualDot).Add(residual)
+		residualDot = newResidualDot
+	}
+
+	return solution
+}
+
+// boundaryStep finds the non-negative step s such
+// that |x + s*d| = radius, assuming |x| <= radius.
+func boundaryStep(x, d linalg.Vector, radius float64) float64 {
+	dd := d.Dot(d)
+	xd := x.Dot(d)
+	xx := x.Dot(x)
+	disc := xd*xd + dd*(radius*radius-xx)
+	return (-xd + math.Sqrt(math.Max(disc, 0))) / dd
+}
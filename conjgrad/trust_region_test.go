@@ -0,0 +1,30 @@
+package conjgrad
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestSolveTrustRegion(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution := SolveTrustRegion(lt, b, 2*realSolution.Norm(), 1e-8)
+	checkSolution(t, solution, realSolution)
+
+	// A prec of 0 must not hang.
+	solution = SolveTrustRegion(lt, b, 1e10, 0)
+	checkSolution(t, solution, realSolution)
+
+	radius := realSolution.Norm() / 10
+	solution = SolveTrustRegion(lt, b, radius, 1e-8)
+	if math.Abs(solution.Norm()-radius) > 1e-8*radius {
+		t.Error("expected norm", radius, "but got", solution.Norm())
+	}
+
+	indefinite := Diagonal{1, -1}
+	solution = SolveTrustRegion(indefinite, linalg.Vector{1, 1}, 3, 1e-8)
+	if math.Abs(solution.Norm()-3) > 1e-8 {
+		t.Error("expected boundary solution but got", solution)
+	}
+}
@@ -0,0 +1,28 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// VerifySolution returns the infinity norm of the
+// true residual b-t*x.
+//
+// It does not depend on any solver's bookkeeping, so
+// it gives a uniform way to check the result of any
+// of the solvers in this package.
+func VerifySolution(t LinTran, x, b linalg.Vector) float64 {
+	if len(x) != t.Dim() || len(b) != t.Dim() {
+		panic("dimension mismatch")
+	}
+	return b.Copy().Sub(t.Apply(x)).NormInf()
+}
+
+// RelativeError returns |computed-exact|/|exact| in
+// the 2-norm.
+// If exact is zero, the absolute error |computed| is
+// returned instead.
+func RelativeError(computed, exact linalg.Vector) float64 {
+	diff := computed.Copy().Sub(exact).Norm()
+	if norm := exact.Norm(); norm != 0 {
+		return diff / norm
+	}
+	return diff
+}
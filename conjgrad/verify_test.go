@@ -0,0 +1,29 @@
+package conjgrad
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/num-analysis/linalg"
+)
+
+func TestVerifySolution(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	solution := Solve(lt, b, 1e-8)
+	if res := VerifySolution(lt, solution, b); res > 1e-8 {
+		t.Error("unexpected residual", res)
+	}
+	if res := VerifySolution(Diagonal{1, 2}, linalg.Vector{1, 1}, linalg.Vector{1, -1}); res != 3 {
+		t.Error("expected residual 3 but got", res)
+	}
+
+	if err := RelativeError(solution, realSolution); err > 1e-6 {
+		t.Error("unexpected relative error", err)
+	}
+	if err := RelativeError(linalg.Vector{3, 4}, linalg.Vector{0, 0}); err != 5 {
+		t.Error("expected absolute error 5 but got", err)
+	}
+	if err := RelativeError(linalg.Vector{1, 2}, linalg.Vector{2, 0}); math.Abs(err-math.Sqrt(5)/2) > 1e-12 {
+		t.Error("expected relative error sqrt(5)/2 but got", err)
+	}
+}
@@ -0,0 +1,66 @@
+package conjgrad
+
+import "github.com/unixpickle/num-analysis/linalg"
+
+// A WarmSolver solves a sequence of related systems,
+// using what it learned from each solve to start the
+// next one.
+//
+// Each solve starts from the previous solution, which
+// is first corrected along the last search direction
+// of the previous solve.
+// This is a heuristic, which helps most when t and b
+// change only slightly between solves.
+// If b changes a lot, the carried state may be no
+// better than starting from zero.
+//
+// A WarmSolver should not be used from more than one
+// goroutine at once.
+type WarmSolver struct {
+	workspace Solver
+	solution  linalg.Vector
+	direction linalg.Vector
+}
+
+// Solve is like SolvePrec without a preconditioner,
+// but it is seeded with the state of the last solve.
+//
+// If the dimension of t differs from that of the last
+// solve, the carried state is discarded.
+func (w *WarmSolver) Solve(t LinTran, b linalg.Vector, prec float64) linalg.Vector {
+	if len(w.solution) != t.Dim() {
+		w.Reset()
+	}
+
+	var guess linalg.Vector
+	if w.solution != nil {
+		guess = w.solution
+		if w.direction != nil {
+			// Minimize the t-norm error of the guess along
+			// the last search direction.
+			residual := b.Copy().Sub(t.Apply(guess))
+			applied := t.Apply(w.direction)
+			if curvature := w.direction.Dot(applied); curvature > 0 {
+				guess.AddScaled(w.direction, w.direction.Dot(residual)/curvature)
+			}
+		}
+	}
+
+	res := solve(t, b, &SolveOptions{Tolerance: prec, InitialGuess: guess,
+		workspace: &w.workspace})
+
+	w.solution = res.Solution.Copy()
+	if res.Iterations > 0 {
+		w.direction = w.workspace.conjVec.Copy()
+	}
+	return res.Solution.Copy()
+}
+
+// Reset clears the carried state, so that the next
+// solve starts from zero.
+// This should be called when the operator changes
+// drastically.
+func (w *WarmSolver) Reset() {
+	w.solution = nil
+	w.direction = nil
+}
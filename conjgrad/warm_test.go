@@ -0,0 +1,24 @@
+package conjgrad
+
+import "testing"
+
+func TestWarmSolver(t *testing.T) {
+	lt, b, realSolution := testProblem()
+	var solver WarmSolver
+	checkSolution(t, solver.Solve(lt, b, 1e-10), realSolution)
+
+	// Re-solving the same system should take no work.
+	res := SolveWith(lt, b, SolveOptions{Tolerance: 1e-10, InitialGuess: solver.solution})
+	if res.Iterations != 0 {
+		t.Errorf("expected converged warm state but needed %d iterations",
+			res.Iterations)
+	}
+
+	perturbed := b.Copy()
+	perturbed[0] += 1e-3
+	expected := SolvePrec(lt, nil, perturbed, 1e-10)
+	checkSolution(t, solver.Solve(lt, perturbed, 1e-10), expected)
+
+	solver.Reset()
+	checkSolution(t, solver.Solve(lt, b, 1e-10), realSolution)
+}
@@ -0,0 +1,89 @@
+package linalg
+
+import (
+	"math/cmplx"
+
+	"github.com/unixpickle/num-analysis/kahan"
+)
+
+// CVector is an ordered list of complex numbers
+// which can be manipulated like a vector.
+type CVector []complex128
+
+// Dot returns the Hermitian inner product of two
+// vectors, which is the sum of conj(v[i])*v1[i].
+// The dimensions of v and v1 must match.
+//
+// The inner product of a vector with itself is
+// always real and non-negative.
+func (v CVector) Dot(v1 CVector) complex128 {
+	if len(v) != len(v1) {
+		panic("dimension mismatch")
+	}
+	summer := kahan.NewComplexSummer128()
+	for i, x := range v {
+		summer.Add(cmplx.Conj(x) * v1[i])
+	}
+	return summer.Sum()
+}
+
+// DotUnconj returns the unconjugated bilinear form of
+// two vectors, which is the sum of v[i]*v1[i].
+// The dimensions of v and v1 must match.
+//
+// Unlike Dot, this is not an inner product: the
+// result for a vector with itself may be complex, or
+// zero for a nonzero vector.
+func (v CVector) DotUnconj(v1 CVector) complex128 {
+	if len(v) != len(v1) {
+		panic("dimension mismatch")
+	}
+	summer := kahan.NewComplexSummer128()
+	for i, x := range v {
+		summer.Add(x * v1[i])
+	}
+	return summer.Sum()
+}
+
+// Copy returns a copy of this vector.
+func (v CVector) Copy() CVector {
+	res := make(CVector, len(v))
+	copy(res, v)
+	return res
+}
+
+// Scale scales v in place and returns v.
+func (v CVector) Scale(c complex128) CVector {
+	for i, x := range v {
+		v[i] = x * c
+	}
+	return v
+}
+
+// Add adds v1 to v in place and returns v.
+func (v CVector) Add(v1 CVector) CVector {
+	for i, x := range v1 {
+		v[i] += x
+	}
+	return v
+}
+
+// AddScaled adds s*v1 to v in place and returns v.
+func (v CVector) AddScaled(v1 CVector, s complex128) CVector {
+	for i, x := range v1 {
+		v[i] += s * x
+	}
+	return v
+}
+
+// MaxAbs returns the max of the absolute values
+// of every component in the vector.
+func (v CVector) MaxAbs() float64 {
+	var res float64
+	for _, x := range v {
+		if a := cmplx.Abs(x); a > res {
+			res = a
+		}
+	}
+	return res
+}
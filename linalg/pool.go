@@ -0,0 +1,50 @@
+package linalg
+
+import "sync"
+
+// A VectorPool caches vectors so that they can be
+// reused instead of being garbage collected.
+//
+// It is safe to use a VectorPool from multiple
+// goroutines at once.
+// The zero value is an empty pool ready for use.
+type VectorPool struct {
+	lock  sync.Mutex
+	pools map[int]*sync.Pool
+}
+
+// Get returns a vector of length n with every
+// component set to zero.
+// The vector may be a cached one or a new one.
+func (v *VectorPool) Get(n int) Vector {
+	if obj := v.pool(n).Get(); obj != nil {
+		res := *obj.(*Vector)
+		for i := range res {
+			res[i] = 0
+		}
+		return res
+	}
+	return make(Vector, n)
+}
+
+// Put returns a vector to the pool so that it can
+// be reused by a future call to Get.
+//
+// The caller must not use vec after putting it.
+func (v *VectorPool) Put(vec Vector) {
+	v.pool(len(vec)).Put(&vec)
+}
+
+func (v *VectorPool) pool(n int) *sync.Pool {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if v.pools == nil {
+		v.pools = map[int]*sync.Pool{}
+	}
+	p, ok := v.pools[n]
+	if !ok {
+		p = &sync.Pool{}
+		v.pools[n] = p
+	}
+	return p
+}
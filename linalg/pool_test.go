@@ -0,0 +1,36 @@
+package linalg
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestVectorPool(t *testing.T) {
+	var pool VectorPool
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				n := 1 + (i+j)%5
+				v := pool.Get(n)
+				if len(v) != n {
+					t.Error("expected length", n, "but got", len(v))
+					return
+				}
+				for _, x := range v {
+					if x != 0 {
+						t.Error("vector was not zeroed:", v)
+						return
+					}
+				}
+				for k := range v {
+					v[k] = float64(k + 1)
+				}
+				pool.Put(v)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
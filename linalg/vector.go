@@ -1,14 +1,27 @@
 package linalg
 
 import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
 	"math"
 	"math/rand"
+	"sync"
 
 	"github.com/unixpickle/num-analysis/kahan"
 )
 
+// RelativeFloor is the smallest denominator used by
+// MaxAbsRelative, relative to the largest one.
+const RelativeFloor = 1e-12
+
 // Vector is an ordered list of floating points
 // which can be manipulated like a vector.
+//
+// A Vector is an ordinary slice, so converting a
+// []float64 with Vector(s) does not copy anything:
+// the Vector aliases the same backing array, and
+// in-place operations like Add modify it.
 type Vector []float64
 
 // RandVector creates a vector with entries sampled from
@@ -22,11 +35,21 @@ func RandVector(size int) Vector {
 }
 
 // Dot returns the dot product of two vectors.
-// The dimensions of v and v1 must match.
+// The dimensions of v and v1 must match; if they do
+// not, Dot panics with a message giving both.
+//
+// The products are added with compensated summation,
+// so Dot is equivalent to DotKahan.
 func (v Vector) Dot(v1 Vector) float64 {
-	if len(v) != len(v1) {
-		panic("dimension mismatch")
-	}
+	return v.DotKahan(v1)
+}
+
+// DotKahan returns the dot product of two vectors,
+// using Kahan summation to add up the products.
+// This avoids much of the rounding error that naive
+// summation accumulates for long vectors.
+func (v Vector) DotKahan(v1 Vector) float64 {
+	checkLengths("Dot", v, v1)
 	summer := kahan.NewSummer64()
 	for i, x := range v {
 		summer.Add(x * v1[i])
@@ -37,9 +60,7 @@ func (v Vector) Dot(v1 Vector) float64 {
 // DotFast is like Dot, but it values speed
 // over numerical accuracy.
 func (v Vector) DotFast(v1 Vector) float64 {
-	if len(v) != len(v1) {
-		panic("dimension mismatch")
-	}
+	checkLengths("DotFast", v, v1)
 	var sum float64
 	for i, x := range v {
 		sum += x * v1[i]
@@ -47,6 +68,46 @@ func (v Vector) DotFast(v1 Vector) float64 {
 	return sum
 }
 
+// DotParallel is like Dot, but it splits the vectors
+// into the given number of contiguous chunks and sums
+// each chunk on its own goroutine.
+//
+// The partial sums are combined in chunk order, so the
+// result is bitwise reproducible for a fixed number of
+// chunks, no matter how the goroutines are scheduled.
+// It may still differ from Dot by rounding, and it may
+// vary with the number of chunks.
+func (v Vector) DotParallel(v1 Vector, chunks int) float64 {
+	checkLengths("DotParallel", v, v1)
+	if chunks <= 0 {
+		panic("chunk count must be positive")
+	}
+	if chunks > len(v) {
+		chunks = len(v)
+	}
+	partials := make([]float64, chunks)
+	var wg sync.WaitGroup
+	for i := 0; i < chunks; i++ {
+		start, end := i*len(v)/chunks, (i+1)*len(v)/chunks
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			partials[i] = v[start:end].DotKahan(v1[start:end])
+		}(i)
+	}
+	wg.Wait()
+	return Vector(partials).Sum()
+}
+
+// DotErr is like Dot, but it returns an error rather
+// than panicking if the dimensions differ.
+func (v Vector) DotErr(v1 Vector) (float64, error) {
+	if len(v) != len(v1) {
+		return 0, lengthError("Dot", v, v1)
+	}
+	return v.DotKahan(v1), nil
+}
+
 // Copy returns a copy of this vector.
 func (v Vector) Copy() Vector {
 	res := make(Vector, len(v))
@@ -54,7 +115,58 @@ func (v Vector) Copy() Vector {
 	return res
 }
 
-// Scale scales v in place and returns v.
+// FromFloat64 returns s as a Vector without copying.
+// The result aliases s, so changes to one are visible
+// in the other.
+func FromFloat64(s []float64) Vector {
+	return Vector(s)
+}
+
+// FromFloat64Copy returns a Vector holding a copy of
+// s, which does not alias s.
+func FromFloat64Copy(s []float64) Vector {
+	return Vector(s).Copy()
+}
+
+// AsFloat64 returns the components of v as a plain
+// []float64 without copying.
+// The result aliases v, so changes to one are visible
+// in the other.
+func (v Vector) AsFloat64() []float64 {
+	return []float64(v)
+}
+
+// ToFloat64Copy returns a copy of the components of v
+// as a plain []float64, which does not alias v.
+func (v Vector) ToFloat64Copy() []float64 {
+	return []float64(v.Copy())
+}
+
+// Slice returns the components from start up to but
+// not including end.
+// The result shares storage with v, so changes to one
+// are visible in the other; use Copy for independent
+// storage.
+// Its capacity ends at end, so appending to it never
+// overwrites the rest of v.
+func (v Vector) Slice(start, end int) Vector {
+	return v[start:end:end]
+}
+
+// CopyFrom overwrites v in place with the components
+// of src and returns v.
+// The dimensions of v and src must match.
+func (v Vector) CopyFrom(src Vector) Vector {
+	checkLengths("CopyFrom", v, src)
+	copy(v, src)
+	return v
+}
+
+// Scale multiplies every component of v by c,
+// modifying v in place, and returns v so that calls
+// can be chained.
+// It does not allocate; to keep v unchanged, use
+// ScaledCopy.
 func (v Vector) Scale(c float64) Vector {
 	for i, x := range v {
 		v[i] = x * c
@@ -62,20 +174,87 @@ func (v Vector) Scale(c float64) Vector {
 	return v
 }
 
+// ScaleInPlace is equivalent to Scale, but its name
+// makes it clear that v is modified.
+func (v Vector) ScaleInPlace(c float64) Vector {
+	return v.Scale(c)
+}
+
+// ScaledCopy returns a new vector equal to c*v,
+// leaving v unchanged.
+func (v Vector) ScaledCopy(c float64) Vector {
+	res := make(Vector, len(v))
+	for i, x := range v {
+		res[i] = x * c
+	}
+	return res
+}
+
 // Add adds v1 to v in place and returns v.
+// The dimensions of v and v1 must match.
 func (v Vector) Add(v1 Vector) Vector {
+	checkLengths("Add", v, v1)
 	for i, x := range v1 {
 		v[i] += x
 	}
 	return v
 }
 
+// Sub subtracts v1 from v in place and returns v.
+// The dimensions of v and v1 must match.
+func (v Vector) Sub(v1 Vector) Vector {
+	checkLengths("Sub", v, v1)
+	for i, x := range v1 {
+		v[i] -= x
+	}
+	return v
+}
+
+// Negate negates v in place and returns v.
+func (v Vector) Negate() Vector {
+	for i, x := range v {
+		v[i] = -x
+	}
+	return v
+}
+
+// AddScaled adds s*v1 to v in place and returns v.
+// Unlike v.Add(v1.Copy().Scale(s)), this does not
+// allocate any memory.
+func (v Vector) AddScaled(v1 Vector, s float64) Vector {
+	checkLengths("AddScaled", v, v1)
+	for i, x := range v1 {
+		v[i] += s * x
+	}
+	return v
+}
+
 // Mag returns the magnitude of this vector using
 // a 2-norm.
 func (v Vector) Mag() float64 {
 	return math.Sqrt(v.Dot(v))
 }
 
+// Normalize scales v in place to have a 2-norm of 1
+// and returns v.
+//
+// If v is zero, it is left unchanged, since it has
+// no direction.
+func (v Vector) Normalize() Vector {
+	norm := v.Norm()
+	if norm == 0 {
+		return v
+	}
+	return v.Scale(1 / norm)
+}
+
+// Unit returns a normalized copy of v, without
+// modifying v.
+// As with Normalize, a zero vector stays zero.
+func (v Vector) Unit() Vector {
+	return v.Copy().Normalize()
+}
+
 // MaxAbs returns the max of the absolute values
 // of every component in the vector.
 func (v Vector) MaxAbs() float64 {
@@ -86,8 +265,152 @@ func (v Vector) MaxAbs() float64 {
 	return res
 }
 
+// MaxAbsRelative returns the max over i of
+// |v[i]|/|b[i]|, which measures v relative to b
+// component by component.
+// The dimensions of v and b must match.
+//
+// To avoid dividing by zero, each |b[i]| is raised to
+// at least RelativeFloor times the largest |b[i]|.
+// If b is zero, the absolute values of v are used.
+func (v Vector) MaxAbsRelative(b Vector) float64 {
+	checkLengths("MaxAbsRelative", v, b)
+	floor := RelativeFloor * b.MaxAbs()
+	if floor == 0 {
+		floor = 1
+	}
+	var res float64
+	for i, x := range v {
+		res = math.Max(res, math.Abs(x)/math.Max(math.Abs(b[i]), floor))
+	}
+	return res
+}
+
+// Norm returns the Euclidean norm (2-norm) of this
+// vector.
+//
+// Unlike Mag, this rescales the components before
+// squaring them, so it does not overflow when the
+// components are very large.
+func (v Vector) Norm() float64 {
+	scale := v.MaxAbs()
+	if scale == 0 || math.IsInf(scale, 0) {
+		return scale
+	}
+	var sum float64
+	for _, x := range v {
+		x /= scale
+		sum += x * x
+	}
+	return scale * math.Sqrt(sum)
+}
+
+// Norm1 returns the 1-norm of this vector, which is
+// the sum of the absolute values of its components.
+func (v Vector) Norm1() float64 {
+	summer := kahan.NewSummer64()
+	for _, x := range v {
+		summer.Add(math.Abs(x))
+	}
+	return summer.Sum()
+}
+
+// NormInf returns the infinity-norm of this vector.
+// It is equivalent to MaxAbs.
+func (v Vector) NormInf() float64 {
+	return v.MaxAbs()
+}
+
+// ApproxEqual returns true if v and v1 have the same
+// length and, for every index i,
+//
+//	|v[i]-v1[i]| <= tol*(1+max(|v[i]|, |v1[i]|))
+//
+// This bound acts like an absolute tolerance for
+// small components and like a relative tolerance for
+// large ones.
+// Components which are NaN are never approximately
+// equal to anything.
+func (v Vector) ApproxEqual(v1 Vector, tol float64) bool {
+	if len(v) != len(v1) {
+		return false
+	}
+	for i, x := range v {
+		y := v1[i]
+		bound := tol * (1 + math.Max(math.Abs(x), math.Abs(y)))
+		if !(math.Abs(x-y) <= bound) {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns a 64-bit FNV-1a hash of the IEEE-754
+// bit patterns of the components.
+//
+// The hash only depends on the data, so it is stable
+// across runs and machines.
+// Since -0 == +0, both zeros hash identically, so
+// vectors which are equal under == have equal hashes.
+// NaN components hash by their bit pattern.
+func (v Vector) Hash() uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	for _, x := range v {
+		if x == 0 {
+			// Map -0 to +0.
+			x = 0
+		}
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(x))
+		h.Write(buf[:])
+	}
+	return h.Sum64()
+}
+
+// Sum returns the sum of the components of the
+// vector, computed with compensated summation.
+func (v Vector) Sum() float64 {
+	summer := kahan.NewSummer64()
+	for _, x := range v {
+		summer.Add(x)
+	}
+	return summer.Sum()
+}
+
+// Mean returns the average of the components of the
+// vector, or 0 if the vector is empty.
+func (v Vector) Mean() float64 {
+	if len(v) == 0 {
+		return 0
+	}
+	return v.Sum() / float64(len(v))
+}
+
+// Variance returns the population variance of the
+// components of the vector, or 0 if the vector is
+// empty.
+//
+// It uses Welford's algorithm, which stays accurate
+// for long vectors with a large mean.
+func (v Vector) Variance() float64 {
+	if len(v) == 0 {
+		return 0
+	}
+	var mean, sumSq float64
+	for i, x := range v {
+		delta := x - mean
+		mean += delta / float64(i+1)
+		sumSq += delta * (x - mean)
+	}
+	return sumSq / float64(len(v))
+}
+
 // Max returns the value and index of the maximum
 // component in the vector.
+//
+// Unlike Mean, Variance and Sum, this also returns an
+// index, since existing callers depend on it; use
+// max, _ := v.Max() when only the value is needed.
 func (v Vector) Max() (float64, int) {
 	if len(v) == 0 {
 		return 0, 0
@@ -105,6 +428,9 @@ func (v Vector) Max() (float64, int) {
 
 // Min returns the value and index of the minimum
 // component in the vector.
+//
+// As with Max, the index is returned for existing
+// callers.
 func (v Vector) Min() (float64, int) {
 	if len(v) == 0 {
 		return 0, 0
@@ -119,3 +445,13 @@ func (v Vector) Min() (float64, int) {
 	}
 	return min, idx
 }
+
+func lengthError(op string, v, v1 Vector) error {
+	return fmt.Errorf("linalg: %s length mismatch (%d vs %d)", op, len(v), len(v1))
+}
+
+func checkLengths(op string, v, v1 Vector) {
+	if len(v) != len(v1) {
+		panic(lengthError(op, v, v1).Error())
+	}
+}
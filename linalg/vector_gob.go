@@ -0,0 +1,47 @@
+package linalg
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const vectorGobVersion = 1
+
+// GobEncode encodes the vector in a compact binary
+// form which preserves every component exactly.
+func (v Vector) GobEncode() ([]byte, error) {
+	res := make([]byte, 1, 1+binary.MaxVarintLen64+8*len(v))
+	res[0] = vectorGobVersion
+	res = binary.AppendUvarint(res, uint64(len(v)))
+	for _, x := range v {
+		res = binary.LittleEndian.AppendUint64(res, math.Float64bits(x))
+	}
+	return res, nil
+}
+
+// GobDecode decodes a vector which was encoded by
+// GobEncode.
+func (v *Vector) GobDecode(d []byte) error {
+	if len(d) == 0 {
+		return errors.New("empty vector data")
+	}
+	if d[0] != vectorGobVersion {
+		return fmt.Errorf("unsupported vector version: %d", d[0])
+	}
+	dim, n := binary.Uvarint(d[1:])
+	if n <= 0 {
+		return errors.New("invalid vector dimension")
+	}
+	data := d[1+n:]
+	if dim > uint64(len(data))/8 || uint64(len(data)) != 8*dim {
+		return errors.New("vector dimension does not match data length")
+	}
+	res := make(Vector, dim)
+	for i := range res {
+		res[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+	}
+	*v = res
+	return nil
+}
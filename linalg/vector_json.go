@@ -0,0 +1,58 @@
+package linalg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const vectorJSONVersion = 1
+
+type vectorJSON struct {
+	Version int       `json:"version"`
+	Dim     int       `json:"dim"`
+	Data    []float64 `json:"data"`
+}
+
+// MarshalJSON encodes the vector as a JSON object of
+// the form {"version": 1, "dim": n, "data": [...]}.
+//
+// Since JSON has no representation for them, an error
+// is returned if any component is NaN or infinite.
+func (v Vector) MarshalJSON() ([]byte, error) {
+	for i, x := range v {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return nil, fmt.Errorf("cannot encode non-finite value %v at index %d", x, i)
+		}
+	}
+	data := []float64(v)
+	if data == nil {
+		data = []float64{}
+	}
+	return json.Marshal(vectorJSON{
+		Version: vectorJSONVersion,
+		Dim:     len(v),
+		Data:    data,
+	})
+}
+
+// UnmarshalJSON decodes a vector which was encoded by
+// MarshalJSON.
+//
+// An error is returned if the version is unsupported
+// or if the dimension does not match the data.
+func (v *Vector) UnmarshalJSON(d []byte) error {
+	var obj vectorJSON
+	if err := json.Unmarshal(d, &obj); err != nil {
+		return err
+	}
+	if obj.Version != vectorJSONVersion {
+		return fmt.Errorf("unsupported vector version: %d", obj.Version)
+	}
+	if obj.Dim != len(obj.Data) {
+		return errors.New("vector dimension does not match data length")
+	}
+	*v = Vector(obj.Data)
+	return nil
+}
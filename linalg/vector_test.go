@@ -0,0 +1,346 @@
+package linalg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestVectorNorms(t *testing.T) {
+	v := Vector{3, -4, 0}
+	if n := v.Norm(); math.Abs(n-5) > 1e-12 {
+		t.Error("expected 2-norm 5 but got", n)
+	}
+	if n := v.Norm1(); n != 7 {
+		t.Error("expected 1-norm 7 but got", n)
+	}
+	if n := v.NormInf(); n != 4 {
+		t.Error("expected infinity-norm 4 but got", n)
+	}
+
+	huge := Vector{3e200, -4e200}
+	if n := huge.Norm(); math.Abs(n-5e200) > 1e188 {
+		t.Error("expected 2-norm 5e200 but got", n)
+	}
+	if n := (Vector{0, 0}).Norm(); n != 0 {
+		t.Error("expected 2-norm 0 but got", n)
+	}
+}
+
+func TestVectorMaxAbsRelative(t *testing.T) {
+	v := Vector{1e-20, -2, 0.5}
+	b := Vector{1e-10, 4, -1}
+	if n := v.MaxAbsRelative(b); n != 0.5 {
+		t.Error("expected 0.5 but got", n)
+	}
+	// A zero entry of b is floored rather than divided by.
+	if n := (Vector{4e-12, 0}).MaxAbsRelative(Vector{0, 1}); math.Abs(n-4) > 1e-12 {
+		t.Error("expected 4 but got", n)
+	}
+	if n := (Vector{3, -5}).MaxAbsRelative(Vector{0, 0}); n != 5 {
+		t.Error("expected 5 but got", n)
+	}
+
+	defer func() {
+		if r := recover(); r != "linalg: MaxAbsRelative length mismatch (2 vs 3)" {
+			t.Error("unexpected panic", r)
+		}
+	}()
+	(Vector{1, 2}).MaxAbsRelative(Vector{1, 2, 3})
+}
+
+func TestVectorLengthMismatch(t *testing.T) {
+	v, v1 := Vector{1, 2, 3}, Vector{1, 2, 3, 4, 5}
+	if _, err := v.DotErr(v1); err == nil ||
+		err.Error() != "linalg: Dot length mismatch (3 vs 5)" {
+		t.Error("unexpected error", err)
+	}
+	if dot, err := v.DotErr(v); err != nil || dot != 14 {
+		t.Error("unexpected result", dot, err)
+	}
+
+	defer func() {
+		if r := recover(); r != "linalg: Add length mismatch (3 vs 5)" {
+			t.Error("unexpected panic", r)
+		}
+	}()
+	v.Add(v1)
+}
+
+func TestVectorAddScaled(t *testing.T) {
+	v := Vector{1, 2, 3}
+	res := v.AddScaled(Vector{1, -1, 2}, 2)
+	expected := Vector{3, 0, 7}
+	for i, x := range expected {
+		if v[i] != x || res[i] != x {
+			t.Fatal("expected", expected, "but got", v)
+		}
+	}
+}
+
+func TestVectorDotKahan(t *testing.T) {
+	// The tiny terms are lost entirely by naive summation,
+	// and the final term cancels out the large one.
+	v := Vector{1}
+	for i := 0; i < 10000; i++ {
+		v = append(v, 1e-16)
+	}
+	v = append(v, -1)
+	ones := make(Vector, len(v))
+	for i := range ones {
+		ones[i] = 1
+	}
+
+	expected := 1e-12
+	if res := v.DotFast(ones); res != 0 {
+		t.Error("expected naive sum to lose all precision, but got", res)
+	}
+	if res := v.DotKahan(ones); math.Abs(res-expected)/expected > 1e-3 {
+		t.Error("expected", expected, "but got", res)
+	}
+}
+
+func TestVectorDotParallel(t *testing.T) {
+	v, v1 := RandVector(10001), RandVector(10001)
+	expected := v.Dot(v1)
+	for _, chunks := range []int{1, 7, 64, 20000} {
+		res := v.DotParallel(v1, chunks)
+		if math.Abs(res-expected) > 1e-10 {
+			t.Errorf("%d chunks: expected %f but got %f", chunks, expected, res)
+		}
+		for i := 0; i < 10; i++ {
+			if v.DotParallel(v1, chunks) != res {
+				t.Fatalf("%d chunks: result is not reproducible", chunks)
+			}
+		}
+	}
+	if res := (Vector{}).DotParallel(Vector{}, 4); res != 0 {
+		t.Error("expected 0 but got", res)
+	}
+}
+
+func TestVectorAliasing(t *testing.T) {
+	buffer := []float64{1, 2, 3, 4, 5}
+	v := Vector(buffer)
+	view := v.Slice(1, 4)
+	view.Scale(10)
+	if buffer[1] != 20 || buffer[3] != 40 || buffer[4] != 5 {
+		t.Error("slice should alias the buffer", buffer)
+	}
+	view.CopyFrom(Vector{7, 8, 9})
+	if buffer[1] != 7 || buffer[3] != 9 {
+		t.Error("CopyFrom should write through", buffer)
+	}
+
+	copied := v.Copy()
+	copied[0] = 100
+	if buffer[0] != 1 {
+		t.Error("Copy should not alias the buffer")
+	}
+}
+
+func TestVectorFloat64Conversions(t *testing.T) {
+	buffer := []float64{1, 2, 3}
+	FromFloat64(buffer)[0] = 10
+	if buffer[0] != 10 {
+		t.Error("FromFloat64 should alias the slice", buffer)
+	}
+	FromFloat64Copy(buffer)[1] = 20
+	if buffer[1] != 2 {
+		t.Error("FromFloat64Copy should not alias the slice", buffer)
+	}
+
+	v := Vector{1, 2, 3}
+	v.AsFloat64()[0] = 10
+	if v[0] != 10 {
+		t.Error("AsFloat64 should alias the vector", v)
+	}
+	copied := v.ToFloat64Copy()
+	copied[1] = 20
+	if v[1] != 2 {
+		t.Error("ToFloat64Copy should not alias the vector", v)
+	}
+	if len(copied) != 3 || copied[0] != 10 || copied[2] != 3 {
+		t.Error("unexpected copy", copied)
+	}
+}
+
+func TestVectorJSON(t *testing.T) {
+	vecs := []Vector{{}, {1, -2.5, 1e-300, math.Pi}}
+	for _, v := range vecs {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded Vector
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if len(decoded) != len(v) {
+			t.Fatal("expected", v, "but got", decoded)
+		}
+		for i, x := range v {
+			if decoded[i] != x {
+				t.Fatal("expected", v, "but got", decoded)
+			}
+		}
+	}
+
+	if _, err := json.Marshal(Vector{1, math.NaN()}); err == nil {
+		t.Error("expected error for NaN")
+	}
+	if _, err := json.Marshal(Vector{math.Inf(-1)}); err == nil {
+		t.Error("expected error for infinity")
+	}
+
+	var v Vector
+	if err := json.Unmarshal([]byte(`{"version":1,"dim":3,"data":[1,2]}`), &v); err == nil {
+		t.Error("expected error for mismatched dimension")
+	}
+	if err := json.Unmarshal([]byte(`{"version":2,"dim":1,"data":[1]}`), &v); err == nil {
+		t.Error("expected error for unknown version")
+	}
+}
+
+func TestVectorSubNegate(t *testing.T) {
+	v := Vector{1, 2, 3}
+	v.Sub(Vector{3, 2, 1}).Negate()
+	expected := Vector{2, 0, -2}
+	for i, x := range expected {
+		if v[i] != x {
+			t.Fatal("expected", expected, "but got", v)
+		}
+	}
+}
+
+func TestVectorApproxEqual(t *testing.T) {
+	v := Vector{1e-9, 1e6, -3}
+	if !v.ApproxEqual(Vector{0, 1e6 + 0.5, -3}, 1e-6) {
+		t.Error("vectors should be approximately equal")
+	}
+	if v.ApproxEqual(Vector{0, 1e6 + 5, -3}, 1e-6) {
+		t.Error("vectors should not be approximately equal")
+	}
+	if v.ApproxEqual(Vector{1e-9, 1e6}, 1) {
+		t.Error("vectors of different lengths should not be equal")
+	}
+	if (Vector{math.NaN()}).ApproxEqual(Vector{math.NaN()}, 1) {
+		t.Error("NaN should not be approximately equal to NaN")
+	}
+}
+
+func TestVectorNormalize(t *testing.T) {
+	v := Vector{3, 0, -4}
+	u := v.Unit()
+	if v[0] != 3 {
+		t.Error("Unit modified its receiver")
+	}
+	if math.Abs(u.Mag()-1) > 1e-12 || math.Abs(u[0]-0.6) > 1e-12 {
+		t.Error("unexpected unit vector", u)
+	}
+	v.Normalize()
+	if math.Abs(v[2]+0.8) > 1e-12 {
+		t.Error("unexpected normalized vector", v)
+	}
+	zero := Vector{0, 0}
+	zero.Normalize()
+	if zero[0] != 0 || zero[1] != 0 {
+		t.Error("zero vector should be unchanged but got", zero)
+	}
+}
+
+func TestVectorScaledCopy(t *testing.T) {
+	v := Vector{1, -2, 3}
+	scaled := v.ScaledCopy(2)
+	if !scaled.ApproxEqual(Vector{2, -4, 6}, 0) {
+		t.Errorf("unexpected scaled copy: %v", scaled)
+	}
+	if !v.ApproxEqual(Vector{1, -2, 3}, 0) {
+		t.Errorf("receiver was modified: %v", v)
+	}
+	v.ScaleInPlace(-1)
+	if !v.ApproxEqual(Vector{-1, 2, -3}, 0) {
+		t.Errorf("unexpected in-place result: %v", v)
+	}
+}
+
+func TestVectorGob(t *testing.T) {
+	v := Vector{1, -2.5, math.Pi, math.Inf(1), math.SmallestNonzeroFloat64}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Vector
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(v) {
+		t.Fatalf("expected length %d but got %d", len(v), len(decoded))
+	}
+	for i, x := range v {
+		if math.Float64bits(x) != math.Float64bits(decoded[i]) {
+			t.Errorf("index %d: expected %v but got %v", i, x, decoded[i])
+		}
+	}
+}
+
+func TestVectorGobInvalid(t *testing.T) {
+	huge := binary.AppendUvarint([]byte{vectorGobVersion}, 1<<61)
+	huge = append(huge, make([]byte, 8)...)
+	for _, data := range [][]byte{nil, {2}, {vectorGobVersion}, huge} {
+		var v Vector
+		if err := v.GobDecode(data); err == nil {
+			t.Errorf("expected error decoding %v", data)
+		}
+	}
+}
+
+func TestVectorStatistics(t *testing.T) {
+	v := Vector{2, 4, 4, 4, 5, 5, 7, 9}
+	if s := v.Sum(); s != 40 {
+		t.Errorf("expected sum 40 but got %f", s)
+	}
+	if m := v.Mean(); m != 5 {
+		t.Errorf("expected mean 5 but got %f", m)
+	}
+	if variance := v.Variance(); math.Abs(variance-4) > 1e-12 {
+		t.Errorf("expected variance 4 but got %f", variance)
+	}
+
+	// A large offset should not ruin the variance.
+	shifted := v.Copy()
+	for i := range shifted {
+		shifted[i] += 1e9
+	}
+	if variance := shifted.Variance(); math.Abs(variance-4) > 1e-6 {
+		t.Errorf("expected variance 4 but got %f", variance)
+	}
+
+	var empty Vector
+	if empty.Mean() != 0 || empty.Variance() != 0 || empty.Sum() != 0 {
+		t.Error("unexpected statistics for an empty vector")
+	}
+}
+
+func TestVectorHash(t *testing.T) {
+	v := Vector{1, -2.5, 3e100, 0}
+	if v.Hash() != v.Copy().Hash() {
+		t.Error("equal vectors hashed differently")
+	}
+	if v.Hash() != 0x3743a20cc12be001 {
+		t.Errorf("unexpected hash %#x", v.Hash())
+	}
+	negZero := v.Copy()
+	negZero[3] = math.Copysign(0, -1)
+	if negZero.Hash() != v.Hash() {
+		t.Error("-0 and +0 hashed differently")
+	}
+	changed := v.Copy()
+	changed[0] = math.Nextafter(1, 2)
+	if changed.Hash() == v.Hash() {
+		t.Error("different vectors hashed identically")
+	}
+}